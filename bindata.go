@@ -69,7 +69,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _readmeMd = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xa4\x54\xdd\x6e\x1b\x45\x14\xbe\x9f\xa7\x38\xdc\xa0\x5a\xb2\xbd\x17\x70\x65\xa9\x42\xa1\x89\xaa\x4a\xa9\x12\x91\x52\x09\x45\x51\x35\x5e\x8f\xed\x21\xeb\xdd\xd5\xce\xac\x6b\x73\x95\x9f\xb6\x80\x5a\x51\x89\x2b\x2e\x90\xe0\x11\x4c\x1a\x27\xa6\x69\xcc\x2b\xcc\xbc\x11\xdf\x99\xad\xeb\x24\x0e\x50\x89\x0b\x27\xb3\xe7\xe7\x3b\xdf\x39\x73\xe6\xdb\xfd\x64\xf7\xcb\x52\x27\x1d\xda\xb1\xd2\x96\x66\xef\x4e\xdf\xda\xdc\xb4\xa2\xc8\x16\x72\xa8\x4d\x23\xd6\xcd\xac\xe8\x45\x85\xda\x6f\x8f\xa3\xae\x51\x23\xab\xd2\x8e\x2a\x9a\x66\xd8\xab\x7d\x6c\x70\x4d\xa0\xca\xbd\x6c\xa8\x0a\xd9\x53\x2b\x85\xe2\xe0\x48\x12\xd3\xd4\x19\x52\xf3\xcc\xac\x00\x44\x6d\xd9\xe9\x29\xae\xf9\x45\xbb\x90\x69\xdc\xbf\x3b\x90\xc6\xaa\xe2\x53\xa3\x8a\xa1\x8e\xd5\xdd\x9e\xb6\xfd\xb2\x5d\xfb\x07\xd0\xca\xbb\x82\x7a\x1d\xab\x26\xc4\x46\x70\x50\x57\x27\xca\x8c\x61\x1b\x90\xcd\x68\x20\x47\x64\xf4\x77\x8a\x9e\x02\x85\x4a\x4e\x4c\xb4\x4e\x7b\x94\xc8\xb1\x2a\x4c\x53\x3c\xb0\x14\xcb\x94\x2a\xd4\x16\xff\xff\xac\xce\x7f\x3f\xaf\xd3\xa8\x6b\xea\xb4\xf9\xf8\x21\x6d\x66\x3d\x1d\xcb\x84\x86\x59\x52\x0e\x54\x65\xdb\xee\x8f\xcd\x8a\xf1\x71\x38\xd3\xfd\x22\x2b\x73\xba\x13\x4a\xa6\xea\x29\x65\x05\x75\x0b\xa5\x28\x1f\xd6\x44\x9d\x72\x59\x58\x6d\x75\x96\x1a\xd2\x29\x3d\xdc\x59\xdf\xda\x21\x09\xe2\xf7\xb7\x1f\x2d\x7d\x64\x65\x1b\x7d\x7c\xe0\x17\x17\x4a\x5a\x15\xd0\xae\xe4\x73\xda\x2d\x64\x0c\x01\xa0\xa3\xcd\x7e\xd5\xf5\x7f\x95\x10\xee\x77\x37\xf1\x87\xfe\x07\x37\xf3\x07\xfe\xb5\x9b\xfa\x23\xf2\xcf\xdc\xc4\xfd\xe9\x2e\xdc\xdc\x9d\xf8\x63\xff\x13\xf9\x43\x78\x0f\xfd\x91\x9b\xba\x77\xfe\x98\xdc\xa9\x9b\x93\x7b\x87\xa0\xb7\xec\x09\xa7\x0b\xff\xca\x5d\x22\xe1\x0d\x5c\xfe\x00\x86\x73\x98\xa7\x7c\xaa\x93\x3b\x09\xe7\x00\x00\x2c\x42\xe0\xcc\x9d\x01\xec\x02\xbf\x33\x94\xff\x31\x80\xcc\xb8\x0e\x8a\x82\x05\x3e\x9a\xc2\xfd\x0a\xbc\xb3\x8a\xd1\xc1\x55\x92\xfe\xc8\xbf\xba\xe5\xb6\x02\xe1\x37\x08\xf9\x9e\x8b\xb9\xb7\x00\x9d\x12\xa3\x3e\xc3\xe9\xfc\x86\x1d\x5c\xe6\x4c\x9c\x47\x78\xdb\xfd\x21\x61\x02\x3e\x21\xe7\x88\x89\xcd\x61\x39\x45\x5f\xe0\xee\x5f\x53\xe8\xf5\xc4\xbf\xf4\xcf\xc5\x2a\xbc\x7f\xbe\x80\x47\x0c\x33\xe0\xe9\xb9\xbf\xf0\xc5\x53\x3a\x67\xeb\x07\x20\x7f\xcc\x2d\x5e\x2f\x70\xc9\xb8\xf5\x50\x83\x1d\x27\x70\xfd\x81\xdf\x69\xe5\xa8\xd5\x17\x03\x3e\xe5\x11\xfa\x97\x1c\x38\xe1\x4b\x99\x85\xf2\x13\x2e\x7f\xc8\x0c\x26\x48\xbb\x80\xf5\x05\x4e\x61\xbc\x57\xd2\x02\xb5\xb0\x1c\x02\x1e\xec\xc6\xf5\x79\x2f\xe9\x80\x6a\x35\xef\x45\xcb\xcc\xf6\x46\xfd\x8f\x98\xf1\xff\x24\x49\x0b\x92\xe2\x6b\xc3\x4a\xa4\x46\x72\x90\x27\xaa\x25\xdc\x6f\x48\x98\x55\xbb\xf6\x2f\x73\x6e\x89\xa5\x78\xd0\x6e\xa3\x01\xa1\x80\x70\xdc\x05\xb1\x27\x6b\x9b\x5f\x6d\xac\xad\x7f\xf3\x64\x7b\x73\xed\xde\xc6\xfa\x1e\x45\xfd\x0c\x6b\x80\x98\x4e\xb6\x27\xc4\x83\xd4\xd8\xa2\x8c\xc3\x9b\x31\x78\xc6\x78\xb5\x25\x33\x68\xda\x91\x15\xee\x17\x5c\x09\xd6\x1a\x4b\x79\x8c\xb7\xf0\x02\x95\xc2\x63\xc1\xc5\xb3\x11\x9f\xbc\xf1\xe8\x61\x99\x22\x98\x45\x91\xe2\xb9\x76\x54\xce\x74\xd2\x58\x2b\x83\x3e\x7e\x06\xd1\x29\x36\xfc\xb2\x5a\xdb\xf3\x30\xfa\xd9\xfb\xf7\x35\x0f\x8f\x67\xd6\x12\x22\xca\x8b\x2c\x8e\x06\x59\x99\x5a\x43\x0d\x80\x58\x15\x5b\x0a\xdf\x94\x67\x1a\x56\x11\x41\x01\x23\x21\xda\xc9\xbe\xee\x2c\x43\x58\x1a\x69\xa1\x8d\xe3\x5c\x09\x03\x31\xbf\x82\x20\xbf\xcd\x8a\xfa\x40\xa7\xd0\xab\xb4\x1c\xb4\x31\xa6\xac\x0b\x27\x6b\x34\xa0\xb2\x78\x1f\x67\x84\xf7\x94\xc5\x1c\x62\x8b\xb0\x20\xad\x1c\xc4\x62\xd3\x80\x3c\x29\x08\x30\xec\x03\x99\xea\xbc\x4c\x58\xb2\x82\x04\xad\x8a\x0e\x5b\xd0\x47\x5b\x21\xad\x50\x50\xb7\xce\xcd\x20\x92\x5d\x8c\x89\xe2\xbe\x4c\x7b\xc8\xa0\x47\x5b\xeb\x5b\x2d\xc4\xe6\x89\x8c\xdf\xc3\x2e\x49\x35\x2a\x0c\xd9\xc9\xad\xf8\x3b\x00\x00\xff\xff\x37\x8d\x38\x74\x15\x07\x00\x00")
+var _readmeMd = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x02\xff\xa4\x57\xdb\x6e\x1b\xc9\xd1\xbe\xef\xa7\xa8\xbd\xf9\x41\x02\xc3\x21\x7e\xef\x22\x08\x08\x18\x81\xbc\xd2\x1a\xc6\x52\xb1\xb0\x92\x05\x64\x0d\xc3\x68\xce\xf4\x90\x0d\xcd\x4c\x0f\xba\x7b\x28\x31\x57\x3a\xec\x3a\x1b\x78\xb3\x06\x82\x5c\xe4\x22\x40\x72\x91\x07\xa0\x65\xd3\xa2\x6d\x89\xfb\x0a\xd5\x6f\x14\x54\x37\x4f\x3a\x78\xb1\x41\x6e\xa4\x61\x1f\xaa\xbe\xfa\xea\xeb\xea\xea\xa7\x9f\x3d\x7d\x50\xcb\x3c\x85\x5d\xcb\x6d\x6d\x9e\x35\x06\xd6\x56\xa6\xd3\x6e\x5b\xcd\x87\xd2\xb4\x12\x19\x2b\xdd\x6f\x6b\x71\xd0\x1b\xb5\x33\x23\x8e\xac\x28\x53\xa1\x63\x33\xec\x37\x7f\xed\xe2\x26\x7b\xfa\xd9\xd3\x2f\xd5\x50\x68\xde\x17\xb7\x1c\x25\x7e\x22\xcf\x4d\x2c\x55\x5b\x8b\x4a\x99\x5b\x06\xda\x3d\x9e\xf6\x05\xf9\xfc\x5d\x4f\xf3\x32\x19\xdc\x2f\xb8\xb1\x42\xff\x9f\x11\x7a\x28\x13\x71\xbf\x2f\xed\xa0\xee\x35\x3f\x61\x34\xcc\xde\xb2\x7a\xdd\x56\x93\xb1\x2d\x3f\x01\x99\xcc\x85\x19\x19\x2b\x0a\xb0\x0a\x0a\x7e\x04\x46\xfe\x51\xc0\xa1\xb4\x03\xa8\x69\x63\x2e\x65\xd9\x87\x9c\x8f\x84\x36\x31\x7b\x64\x21\xe1\x25\x04\xab\x1d\xfa\xff\x79\x44\x7f\xbf\x88\xe0\x28\x33\x11\x64\xf7\xe8\xef\x30\xe3\x36\x82\xdf\xef\x7d\xb5\x1b\x81\x39\xe4\x55\x04\xdd\xfd\x6d\xe8\xaa\xbe\x4c\x78\x0e\x43\x95\xd7\x85\x08\x63\x3b\x83\x91\xb9\x35\xb8\xef\xbf\xe1\xa1\x56\x75\x05\x0d\x0f\xa5\x14\x87\xa0\x34\x64\x5a\x08\xa8\x86\x4d\x16\x41\xc5\xb5\x95\x56\xaa\xd2\x80\x2c\x61\x7b\x77\xf3\xf1\x2e\xf0\x32\x85\x87\x3b\x7b\xab\x39\xb0\xbc\x97\x0b\x13\x41\xae\x54\x05\xa9\x20\xfe\x0c\x34\x7a\x23\xe8\x6b\x75\x68\x07\xa0\x32\xe8\xf1\xe4\x80\x42\x24\x26\x9a\xd1\x7c\x11\x14\xbc\xaa\x84\x5e\x6c\x61\x1e\x44\x2e\x4b\xc1\xb5\xf7\x62\xac\x96\x95\x48\xc1\x72\xdd\x17\xd6\x40\x23\x2d\x8c\xb0\x75\x15\x79\xe6\x54\x6d\x29\x90\xe6\x92\xaf\x44\x0b\x6e\x85\x8f\x62\x0d\x37\x19\xba\x83\x04\x03\xaa\x84\x54\x9a\x83\x90\x85\x5f\x0e\x2d\x86\x4d\x5a\x49\x4e\x7a\x62\x0e\x97\x5d\x03\x0f\x8d\xa2\xce\xad\xac\x38\x85\xab\x41\x16\xbc\x4f\x71\x4a\x6b\xae\x61\xd1\xf3\x98\x53\xe0\x06\x0e\x68\xe6\x08\x52\x25\x0c\x48\x3b\xf7\xa1\x32\x18\x4a\x6d\x6b\x9e\x43\xc1\x93\x81\x2c\x05\x01\xed\xee\x93\x55\xe2\x97\xcd\xfd\xcd\xb1\xcc\x95\x97\x86\x28\xc8\x5d\xce\x8d\x5d\x0b\xa0\xd1\x6a\xf5\x6b\x61\x6c\x33\x22\x97\x9a\x1f\x06\x6c\x3e\x11\xd0\x58\x49\x77\x0e\x39\x66\x5f\xad\xb4\xaa\xf4\x5d\xcc\x2d\x5c\x57\x39\x4f\x44\x4a\xe8\x0e\x07\x2a\x17\x2b\x32\x55\xbd\x0e\xc0\x33\x08\x8d\x24\x57\x75\x0a\x29\xb7\x7c\x91\x80\x66\x07\xa4\x05\x69\xd8\x32\x06\x9e\x59\xa1\xbd\x68\x88\x06\x3b\x08\x36\x63\xc6\xf0\x5f\x38\x76\x27\xee\x07\x9c\xba\x63\xf7\x0a\x27\xee\x14\xdc\x77\x38\xc6\xf7\xf8\x11\x67\x78\xee\xce\xdc\x4f\xe0\x4e\x70\xea\x4e\xdc\x29\x4e\xf0\xd2\x9d\x01\xbe\xc5\x19\xe0\x25\x8e\xf1\x03\xcd\xf8\xaf\x8f\xee\x47\xbc\xc2\x19\xbe\xc1\x19\xb8\x63\x1c\xe3\x05\x5e\xe2\x84\xbe\x22\xc0\x73\xff\xed\x0d\x80\x3b\x01\xbc\xc2\x29\xbe\xc3\x09\x7e\xc4\x09\xbe\xc3\xb1\xfb\xb3\x37\x32\x25\x3f\x1f\x71\xe6\x5e\xd1\x8f\x98\xe1\x3f\x70\x86\xef\x02\xa2\xe3\x75\x90\xee\xd4\xfd\xf8\x5f\x1c\x5d\x1f\xc8\x1b\x9c\xba\x3f\x11\x08\xfc\x80\x53\x9c\x00\x79\xfb\x0e\xa7\x78\x71\x63\xdc\x9d\xe2\x8c\x02\xa2\xec\xdc\x75\x98\xf1\x02\xc7\xe0\x4e\xfc\x9e\x53\x02\x3c\xc3\x0b\x7c\x8b\x63\x8a\xc9\xbd\x02\xcf\xc1\xb9\x7b\xe9\xbe\x67\xb7\xcd\xbb\xef\x17\xe6\x67\x78\x4e\x08\x88\x55\xfc\x19\x67\x9e\xbd\x0b\x1a\x5d\x1a\x72\x67\x14\xfa\x75\x07\x57\x64\x37\xf2\x3e\x68\xe2\x1c\x67\xf8\x1a\x67\xf8\x36\x4c\x34\xa3\x05\xf1\x6f\x89\x5a\xf7\x92\x16\x8e\x29\x59\x53\xef\x7e\x4c\xee\x4f\x08\xc1\x18\x5f\xe3\x47\x9c\xba\x17\x38\x0e\xb4\xaf\x6d\xf3\xd0\xfc\x89\x65\x38\xa5\x03\x1b\x4a\x4f\xcb\x9d\x51\xfe\xdc\x31\xce\xf0\x3d\x7d\x11\xd4\x5b\x6c\x9c\xe1\xb9\xb7\xe1\x63\x5e\x30\xe2\xc3\x5e\x53\x14\xe1\xbc\xcb\xd8\xf5\xba\x45\x48\x5f\xf8\x38\xbc\x1a\x16\xa5\x0b\xa7\xcb\xca\xb5\xaa\x58\xf8\x1a\x27\x78\xc1\x42\xc1\x5a\x57\xcd\x8a\xbc\x73\x1c\x07\xd5\x2c\x12\x84\x93\x6b\x61\xbb\x97\xbf\x4a\x11\xff\x23\xa5\x10\x28\x8d\x01\xff\x16\x2c\x00\x5e\x2e\xd1\xe2\x6b\xf7\x92\x30\xb2\x3b\xd8\x99\xe1\xe5\x0d\x7e\xd6\x6a\x22\x4e\xc9\x35\x90\x1a\x82\xdb\x66\x04\x38\x59\x3f\x89\xcb\x10\x67\xee\x74\xb9\x8c\x0e\xde\x4f\xee\xd4\x9d\xb8\x57\x21\x82\x0f\x40\x48\x22\xa0\xc0\xf0\x03\xb8\xbf\xd0\x62\x98\xc7\x30\xf6\x18\x43\x59\x5d\xe1\x67\x78\xee\x8f\xe4\xa9\x3b\x5b\xab\x01\xef\x7d\x71\xa0\xd3\x4a\xca\x0c\x9c\x75\xf7\x17\x38\x3f\x25\xa7\xc9\x3a\x19\xb7\x0f\xbc\xc7\x79\xab\x92\xfc\x8c\x33\x5f\x34\x26\x74\x0a\xa8\x8a\xdc\x64\xfe\x72\xbd\x4a\xcf\x23\xc3\xe9\x52\x90\x6c\xc5\x1a\x8e\xef\xac\xd9\xf8\xef\x95\x74\x71\xec\x5e\x5d\x2b\x86\x38\x5e\x44\x75\x87\x74\xde\xcf\xa5\x13\x84\x43\xb1\xbd\x71\x67\x94\xe8\x2b\x12\x8e\x3f\xb8\xd3\x55\x64\x5e\x58\xe7\xee\x84\xcc\xae\x14\x36\x99\x8b\xfb\x9a\xc6\xdc\xcb\xdb\xfa\x6a\x2c\xb7\x4c\x61\xbd\x5c\x00\x55\x9a\x99\xdf\x39\x0e\x8a\x6d\x76\x28\xe8\x2b\x9c\xde\x24\x79\x21\x86\x15\xa9\x9f\x38\xd1\x4b\xf9\xc7\x8c\x3d\x31\x74\xe3\x89\x23\x5e\x54\xb9\xe8\x30\xfc\xa7\x3b\xf6\xc5\x7c\xe2\x8e\x7f\xa1\xbc\x75\xd8\x1a\xd3\x4f\x5b\xad\x4c\xe6\x56\xe8\xfb\xdd\xfd\xed\xe7\x1b\xdd\x6f\xb6\x36\x36\xff\xf0\x7c\xa7\xbb\xf1\xe5\xd6\xe6\x33\x68\x0f\x54\x21\x68\x4d\xaa\x9e\x31\xf6\xa8\x34\x56\xd7\x89\xbf\xfd\x8c\x10\x20\x4b\xa8\x09\x41\x6c\x8f\x2c\xc3\xbf\xe3\x55\x90\x95\x3b\xc3\x0f\xee\x05\x4e\xc3\xdd\x75\x49\xba\x27\x5c\x5e\x36\x78\xbe\xb6\xc5\x5f\x90\xba\xe4\x39\xa4\xa2\x22\x38\x65\x22\x85\xe9\x30\xfc\x2b\x5e\xe1\xc4\xfd\xe0\x45\x35\x01\x52\x87\x97\x7a\xb8\xee\x66\xe4\x05\xa7\x1d\xc6\xda\x95\x56\x49\xdb\x88\x3c\x6b\x17\xaa\x2e\xad\x2c\x33\x05\x2d\x48\x85\x15\x89\x05\x3f\x04\x95\x92\xa5\x35\xac\x6d\x46\xa6\x0d\xad\xc5\x39\x2e\x79\x71\xab\xaf\x5b\xef\xe4\x0c\xeb\xe5\x2a\x39\x58\xce\xb5\x40\x0b\x9e\x82\xa9\x2b\xa1\xfd\x8c\x81\x4c\xe9\x85\xa7\x44\x95\x56\x94\x36\xb4\x76\xd4\xff\xaa\x6c\xbd\x33\x6e\x88\x23\x7b\xaf\xfd\x79\x7b\x71\x55\xf6\xac\xa6\x7f\xdd\xfd\xed\x7b\xb0\xb3\x1f\x41\xf7\xc9\xd7\xbb\x11\x0b\xd7\x65\x91\x2e\x2e\xd1\x70\xa1\xd2\x55\xda\x5c\x36\x1f\x21\x24\x72\xd3\xcb\x0f\x64\xda\xb6\x75\x29\xee\x65\xa6\x7d\x94\x99\xe7\x14\x7c\x0c\x9b\xf3\xf8\xea\xa2\x27\xb4\x89\x02\x1c\x8f\x4b\x24\x56\x69\xff\xdb\x84\x60\x7a\x23\x30\x96\x5b\x66\x46\x26\xe1\x79\xee\x57\x3d\xe8\x7e\xfd\x70\x6b\x6f\xf7\xd1\xb7\x5b\xbf\xf9\xa2\xfd\xa0\xfb\xf5\xee\xee\xb7\x0f\xb7\xf6\xe8\x6b\xe7\x81\xff\x04\xa9\x12\x9b\x9b\x98\x79\xbc\x2a\xcb\x22\x28\x0e\x02\x74\x3f\x50\x42\x6b\xde\xba\xf9\xdf\x31\x6c\x24\x56\x0e\x85\xff\x01\xd2\x80\xad\x75\x49\xbd\x55\x96\x81\x2a\xf3\x11\xc8\x2c\x74\xe5\xdf\x6c\x6c\x43\x63\x5b\x14\x1b\x43\x2e\x73\x6a\xac\x9a\xb4\x5a\x94\xaa\xee\x0f\x88\x6a\x46\x1d\x60\x6d\x44\x0a\x15\xef\x8b\xd0\xfc\x6a\x41\x6f\x1a\x01\xff\x7f\xef\xb7\xdb\xf2\x41\x04\x22\x37\xbe\x79\x2b\x69\xab\x39\x90\xd4\x8e\xc6\x73\x78\x70\x20\x44\x65\xe0\xc9\x93\x47\x9b\x7e\x6f\xce\x7b\x22\x8f\x19\xd3\x82\x18\x89\x03\xd9\x19\xb7\xe1\x77\x04\xa5\xcd\x4c\xf8\x5e\x05\xb4\x6a\x71\xbc\x09\x4a\x4d\x0c\x7b\x03\x31\x5a\x74\x76\x59\x1e\xba\xda\x7c\xd4\x09\xa9\x12\xd7\x5e\x48\xd2\xb0\xba\x5c\x8c\x37\x64\x46\xba\xc9\x64\x1f\x78\x9e\xab\x43\xd3\x81\xda\xcf\xc1\x7d\x18\x09\xd3\xf4\x2e\x16\x8b\x49\x9a\xa1\x11\x36\xbc\x10\xa0\x2a\xdf\x75\xc7\x8c\xcd\x6f\xe1\x85\x3e\x69\xcf\x1c\x6c\xe8\xef\x49\x89\x77\x3e\x49\x22\x38\x1c\xc8\x64\xe0\x1b\xf7\x52\xf9\xf7\x46\x0c\x5d\xea\xb0\xc3\x9b\xc4\x87\x64\x48\x23\x3e\x3d\xa6\xe2\x89\x08\x6d\x2c\x93\x16\xe8\xfc\xab\x5a\x27\x62\x65\x8e\x1e\x27\xde\x27\x91\x9f\x2b\xbe\x6c\xdd\x4d\x6d\xe8\x6c\xb7\xb5\x30\x75\x21\x62\xc6\xc2\xe3\xa0\x05\x56\x14\x95\xd2\x5c\x8f\xd6\x1f\x12\x2a\x5b\x3f\x94\xc1\x82\xbf\x40\x42\x17\xbe\xbc\x4e\x62\xc6\x72\x35\xef\x40\x86\x7d\x2d\xe8\x40\xd3\x57\x32\xe0\x65\x9f\x72\xc6\xad\xe5\xc9\x60\xed\x5d\x70\xc7\xfb\xe3\x8e\x1b\xc7\x5f\x17\x2a\x9b\xef\x91\x06\xa4\x51\x39\xf7\x89\xd4\xaa\x80\x81\x32\x96\xf5\x46\x0b\x78\xa1\x72\x52\x1d\x6c\xb5\x42\x32\x23\xd8\x7f\x18\x50\x97\x3e\x53\x99\xdf\x42\x83\x92\x8e\x1d\x0d\xa6\xcb\xc8\x65\x3e\x8a\x19\xab\x53\x31\xe4\x69\x01\x2d\x38\xe4\xd2\x02\xfd\x0c\x4c\x93\xc0\xe9\x49\x42\x39\x94\xe6\xc0\x50\xf4\x61\xa8\x39\xa7\xb1\xd2\xaa\x27\x7c\xf2\x7d\xfa\x6f\xbe\x4d\x82\x95\x40\x89\x89\x61\xef\xf1\xe6\xe3\x0e\x68\xe1\xdf\x37\x01\xa4\xaf\x65\xe4\xb0\xd5\x0a\x36\x78\x5a\x59\xb6\x73\x2d\x1f\x37\xf4\x13\x90\x2c\x1b\xa0\x28\x50\x45\x82\xd5\x02\xea\x2a\xe5\xf6\xda\x23\xb0\x55\x2f\xde\x81\x1d\x52\xd3\xad\x87\xee\x32\x8c\x98\xfd\x67\x00\x3a\xb7\x3c\x4a\x60\x11\x00\x00")
 
 func readmeMdBytes() ([]byte, error) {
 	return bindataRead(
@@ -84,12 +84,12 @@ func readmeMd() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "README.md", size: 1813, mode: os.FileMode(436), modTime: time.Unix(1449659147, 0)}
+	info := bindataFileInfo{name: "README.md", size: 4448, mode: os.FileMode(436), modTime: time.Unix(1792369312, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _usageTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9c\x57\x5f\x6f\xd3\x56\x14\x7f\xcf\xa7\x38\x0f\x48\x6b\x59\x9a\xb2\x3d\x4d\x15\xd3\x94\xd1\x0c\xa1\x41\x8b\x0a\xea\x84\x50\x8b\x9c\xf8\xa6\xb5\x70\xec\xcc\x76\x1a\xf2\x96\x86\x41\x99\xca\xa8\xf6\xb0\x17\xa4\xb1\x4d\xda\x07\x08\xa5\x6e\xd3\xb4\x49\xbf\x82\xfd\x8d\xf6\x3b\xe7\xda\x89\xf3\x87\x31\x6d\xd2\x1a\xfb\xde\xeb\xf3\xe7\x77\x7e\xf7\x77\x0e\x55\x5f\x3d\x0b\x94\x63\x2a\x8f\x1e\x2f\x2d\x55\x2d\x3b\x50\xde\xd7\x77\x37\xef\x3d\x29\xde\xdd\x28\x15\x57\x1f\x3d\xb9\x7f\xb7\x78\xab\xb4\xba\x45\xcb\xbb\x6e\x4d\xf1\x19\xd3\xdd\xca\xe5\xf8\x87\x96\x08\x7f\x6a\xae\x69\x55\x5b\x54\x37\xbc\xc0\x0a\x2c\xd7\xf1\x69\xa1\x69\x05\xbb\x6e\x23\xa0\xba\x67\x39\xf8\x6b\x1b\xce\x62\x21\x47\xfa\xbf\x1f\x92\xbd\xc4\xc0\xf8\x48\x21\x97\x1e\x89\xfe\x88\xdb\x51\x2f\xba\x8c\xc2\x68\x10\xf5\xe2\x4e\xfc\x9a\xf0\x7a\x96\x2c\xe8\xc5\xa3\xd1\xe1\x5f\xb1\x72\x96\x9a\x8b\xae\xa2\x30\x3e\x88\xba\x71\x27\xea\xe2\xa9\x13\xef\xc7\x47\xbc\x78\x81\xd7\xc1\x8c\x95\xe8\xbc\x40\xf8\x1d\x92\xbc\xf4\x71\xa6\x0f\xd3\x2f\x28\x1a\x8a\x9d\x36\xec\xbc\xe4\x53\xbc\x1f\x52\x74\x1c\x1f\x62\x7d\x08\x63\x83\xf8\x28\xb5\xce\x50\x68\xd4\xf2\xb4\x54\x45\x08\xfa\x85\xca\xb6\x5b\x79\x4a\xa6\xda\xb3\x2a\xca\xa7\xaa\xeb\x91\xc6\x99\x80\x2d\xed\xb9\x76\x03\x60\xee\x78\x6e\xa3\xae\x91\xb1\xaa\x64\x05\xa4\x7e\x6c\x18\x36\xcd\xa2\x4f\x0b\xa6\xaa\x1a\x0d\x3b\x58\x84\x03\x31\xb0\x93\x9a\x73\x1d\xbb\x45\xe5\x16\xf9\x75\xa3\xa2\xf0\x46\xa6\xe5\x3f\xd5\x26\x1d\x6a\xee\x5a\x95\x5d\xba\xbf\x49\x6e\x95\x82\x5d\x45\xf6\x5e\x8d\x36\x6f\x93\x61\x7b\xca\x30\x5b\x0c\x7b\x45\x99\x05\xba\x13\x50\xc5\x70\xa8\x82\xd5\x40\x91\xa3\x9a\xd9\x6a\x1a\x70\x92\xf8\x52\xcf\x2c\x3f\xc0\x07\x62\xfe\x4e\x95\x5a\x6e\x83\x9a\x06\xea\xe7\xb8\x64\x5b\x35\x24\x10\xb8\xd9\x34\x1b\xbe\x22\x55\xab\x07\xad\x04\x94\x15\x1a\x31\x6c\xc6\x84\xdb\x74\xb4\x8d\x15\x6a\x7a\x16\xc2\xf0\xd4\x8e\x7a\x56\x27\xe6\x12\x9f\xf2\xc8\x6b\xd8\xca\x2f\xd0\x23\x7c\xc1\xd1\xb2\xf1\x9a\xe1\xb4\xf4\x7a\x9e\x7c\x85\xa0\x11\xbf\x29\xa6\x81\x48\xc5\xad\xd5\x8c\x02\x7d\x27\xd0\x1b\xb5\xba\xad\x32\xfe\x97\x51\x99\x65\xdf\x34\xf2\xc9\x43\x39\x0d\x88\xad\x91\x1f\x20\x7f\x5f\xfb\x5e\x06\xe4\xc8\xac\xa6\xe0\xd3\x28\xfb\xa8\x1c\x82\xab\x1b\xd8\x61\x64\xe4\x78\xdd\x53\x75\xce\x59\xce\x6f\xd3\x42\x75\xec\x92\x52\x47\x85\xeb\xe2\x01\x27\x05\x74\x46\x6a\x7b\xbc\xb7\x38\xe1\xde\x74\x95\xef\x7c\x86\xa2\xb8\x4e\x60\xa0\x8c\x9c\x25\x2a\x58\x33\xfc\xa7\x54\xd9\x45\x96\x15\xa4\xe0\xaf\xd0\xf6\xf5\xcf\xbf\x79\xbc\xa5\x8b\x1d\x90\x85\x5a\xd5\x39\x0e\x95\x44\xf2\x78\x7b\x79\xeb\xfa\xb5\x84\x04\x12\xff\x12\x61\x3b\xc9\x8b\x8d\x8e\x8d\xe5\xa9\x8c\x4b\x59\x75\x6d\x16\x82\x04\x4a\xd7\xd3\x95\x9e\x40\x30\x8d\x19\x46\x6c\x9b\xca\x6a\x7e\x46\xda\x75\x2e\xcd\x2a\xcb\xf7\x29\xf6\x31\x4d\x99\xb2\x79\x10\xd4\x40\x12\x3b\x8e\xeb\x61\xb5\x9c\x72\x06\x31\x33\x73\xef\x6f\xfa\x7c\x32\xdd\x36\x3d\x6b\x4f\x89\xf5\xa6\xcb\x48\x21\x0e\xcd\xbb\x24\x0f\x4f\xa9\xe4\x46\xe0\x23\xfd\xfd\x28\x60\x10\xc7\x9b\xbe\x90\x9b\x12\x60\x22\x41\xd1\xdf\xb8\xf4\x17\xf1\x6b\x5c\xf0\x36\xae\xfb\x31\xcb\x09\x6b\xd0\x7b\xdc\xfc\x21\xd4\x65\x00\x1d\x08\x29\x7e\x8e\xeb\xaf\x4f\x9c\xf3\x13\x9f\xcb\x13\x04\xa6\x4b\x78\x3d\x60\x7d\x20\xe8\xc9\x10\x3b\xc3\xb8\x1d\x1f\xb2\xae\x5c\xe2\xf0\xa9\xec\x88\xb8\xec\xc7\xaf\xa0\x37\xed\xf8\x88\xed\x8b\x54\x8d\x63\xb9\x3d\xd6\x86\xe8\xb7\x78\x1f\xae\x7b\xf2\x11\xdc\xb0\x62\xcd\xd3\x08\x16\x27\x84\x25\x5e\x2e\x58\x05\x45\x29\xdf\xa4\x9a\xf1\x69\xef\x1c\x2a\x27\xae\x35\x31\x9b\x89\xc4\x81\xed\x90\xb3\x38\xc1\x67\xfb\x9c\x5a\x74\x8c\x84\x3f\xe0\x3d\x64\xc9\x1c\xb0\xef\x53\x7e\x1e\xc0\xfa\x0b\xac\x9c\x88\x7a\xb3\xe5\x05\x71\xfe\x01\x98\x09\x28\x10\x5a\x98\xc6\xca\x19\xce\x74\x53\x84\xb5\x58\x0f\xd8\xae\x46\x98\xd3\xe5\x13\x21\x82\x3a\xcc\x93\x88\x7a\x9f\x12\x20\x66\xe3\xd7\x41\xee\xc3\xc9\xcf\x08\x54\x4a\x82\xe7\x37\x78\xeb\x45\xe1\xe2\x14\x96\xec\x83\x38\x4a\x1c\xeb\x71\x66\x24\x8f\x13\x4d\xe7\x18\x67\x25\xb5\x0f\x12\x0a\xaf\x1f\xa4\xfd\x87\x61\xb8\x60\xcc\x32\xa1\x8c\xf6\x04\x6e\x06\xe9\x2a\x01\xf4\x0c\xd0\x9c\x6b\x2f\x57\x9a\x38\x4c\x1b\x8a\x7f\x1a\x33\x6d\x5a\x1c\xff\x2d\x52\x94\x86\x81\x93\x28\x71\xea\x18\xc6\x7a\x6c\x59\xf3\xa3\xc7\xed\x6e\x6e\xd8\xd1\xf9\x8a\x54\x07\x71\xf5\x24\xe4\x8e\x86\x39\xe4\xd2\x70\x3a\x78\xd6\xec\x66\xa7\xf2\xf5\xe9\x28\xa9\x78\x9f\xa4\x52\xaf\xa4\x37\x4f\xfb\xe3\xa5\x04\xe2\xdf\x19\x7d\xe1\x07\xa7\xde\x67\x2e\x4d\x59\xe3\x96\xaa\xd9\x28\x4c\xd3\xcd\x96\x1b\x37\x63\x76\xa1\x2b\x4a\xc2\xbc\xb6\x74\x77\x49\xf8\x4a\xd6\x51\xd0\x4f\xca\xf8\x18\xba\x6c\x88\x43\x4d\x4c\x38\x11\x08\xd2\xe9\x00\x69\xb1\xc4\xc7\xbf\x70\x4d\x48\x2a\x36\x90\x0c\x33\x03\x84\x66\x2c\x83\x2e\xac\xbd\x00\xa9\x3a\x02\xd4\xb9\xae\xa7\x1e\x51\x46\x89\x44\x27\x53\x9e\xa3\x4b\xa6\xcb\x10\x0a\x22\x4b\x89\xd9\x6d\xa6\x74\x21\x0a\x13\xd8\x26\x63\x1d\xf7\x06\x9d\xfd\x98\x98\xc9\x2d\xe9\x66\xfb\xc7\x54\xda\x80\x31\xb9\x80\x7c\x9b\xc2\xe8\x6a\x0e\x12\xa1\xbe\x81\x27\x12\xf2\x29\x5b\x26\x21\x6c\x18\xbf\x2c\xf0\x13\x43\x70\x2c\xd3\x0e\xee\xe3\x1c\x92\xb0\x12\xcc\x94\x75\xa2\x27\x25\x80\x4e\x3a\x3e\x91\xe1\x4a\x86\xa8\x51\x36\x23\x21\xed\xcb\xad\xe0\xe6\x71\x0d\xd8\x1c\x68\x03\xac\x12\xba\x70\x52\x11\x9e\xf2\x50\x80\xe8\xbd\xd6\x88\x4c\xa0\xac\x11\x51\x5f\x0c\x5d\x4e\xc9\x87\xa6\xba\x5c\x58\x78\xef\x4a\x04\xfd\x11\x5d\xbb\x12\xa4\x4c\x9c\x71\x7b\xdc\xe1\xe0\xe2\xb9\xe0\xd3\xc9\x96\x20\x4c\x27\xc6\xee\x6c\xbb\x5b\x55\x81\xaa\x04\x68\x89\x3e\x66\xb5\x95\x9c\x0c\xb2\xdc\x40\x18\xe2\x3e\xcb\xa9\xd0\x59\x50\x7c\xcd\x6a\xc6\xff\xcf\x55\x10\x74\xa2\x07\x81\x89\x39\x79\x85\xd6\xbf\x87\x9d\x74\xaa\x65\x9e\x0d\xb4\x2e\x77\xe4\x6a\xb3\x0a\xb4\xc7\x64\x64\xe1\x64\x2c\x4e\x70\xcf\xdf\x45\x6f\x25\xc1\x92\x9e\xef\x4d\x1e\x88\xea\xca\x56\x05\xda\x50\x41\xc3\xc3\xbc\xe7\x9a\x8a\x6e\x24\x28\xfc\x39\x1d\x44\xaa\x33\x12\x3d\x36\x74\xde\x2c\x5e\x42\x92\x57\xfc\x8a\xf1\xf9\x2d\x7b\x23\x71\x7a\x06\xf7\xed\x24\xa9\x1b\x99\x0c\xd6\x4a\xe8\x4e\x1b\xa5\x6f\xd7\xd7\x1f\x52\x71\x6d\x95\x1e\x3c\x2c\x6e\x3c\xa4\x7b\x25\x5a\x5f\xbb\x55\xa2\xe2\xed\xe2\x9d\xb5\xc2\xff\xcb\xf1\x3f\x59\xe6\xf4\xd6\x14\xf2\xf7\x54\xd9\x75\x83\x64\x6c\x73\xf4\x7c\x98\x4e\x6d\xbe\x51\xe3\xa9\xce\xc3\x0f\x8f\x43\x93\x18\x7d\xf1\xe5\x57\x29\x57\xa4\xc5\x65\x45\x4b\x30\x82\xd0\x62\xe3\x2c\xed\x1b\xef\xa2\xbf\x44\x0e\xb4\x6a\x69\xb9\xd7\x5b\x13\xb5\x4e\x39\xc9\x1a\x2e\xcd\x55\xcb\xaa\xb4\xd0\x2b\xce\x59\x73\x4e\xee\x46\x57\xef\xe9\x9e\x33\x53\x97\x5e\xd2\x88\x20\x30\xe8\x12\xf1\xe1\xc7\xeb\x22\xa9\xe4\x6e\xd0\x4d\xba\xc5\x99\xdd\xe4\x05\x3d\x1b\x2a\xcf\x93\x99\xc8\x0a\x0a\xb2\xff\x31\x0b\xfa\x93\xa5\xd9\x7b\x09\xd7\xdc\x1a\xde\xe3\x0a\x3e\x9f\xf8\xc7\x52\x86\xd4\xff\x04\x00\x00\xff\xff\xeb\x48\xb8\xc3\x6e\x0e\x00\x00")
+var _usageTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x02\xff\xa4\x7c\xef\x6f\x1c\xc7\x91\xe8\xf7\xfd\x2b\x0a\x78\x01\xb2\xcc\x9b\x59\xca\xb2\x5f\x5e\x1e\x13\xbd\x83\x6c\xd1\x82\x2e\xb4\x24\x58\x32\x93\x9c\x61\x0b\xc3\xdd\x5e\xee\x44\xb3\x33\x9b\xe9\xd9\x25\x37\xb8\x0f\x22\x19\x59\x32\xe4\x48\xb8\x5f\x38\x20\x80\xe3\x04\x17\x1c\xf2\xe5\x80\x15\xc5\x95\x56\xfc\xb1\xfc\x17\x7a\xfe\xa3\x43\x55\x75\xf7\xf4\xcc\xce\x92\x72\xce\x1f\x2c\xee\x4c\x77\x75\x55\x75\x75\xfd\xee\xe9\x4a\xb1\x9b\x89\xb8\x23\x52\xf8\xdc\xf7\xbb\x61\x94\x89\xf4\xda\xc6\xe6\x27\x0f\xae\x6f\x7c\xba\x7e\xfd\xc6\xaf\x1e\xdc\xdd\xb8\xfe\xd1\xfa\x8d\x2f\x60\xb5\x97\xf4\x05\x8e\xe9\x24\x5f\x34\x9c\x59\xbe\x1f\x44\x11\x3e\x6f\x27\x71\x37\xdc\xbe\xb6\x2a\xb2\xf6\x6a\xf1\xbe\x85\x8f\xbf\xa8\x99\xc7\xf0\x7c\x5f\x06\x23\xe1\x0f\xa2\x20\xbe\x86\xff\x6b\xfd\x5a\x26\xb1\x3b\xec\xb3\xcf\x6e\xdd\xb8\x76\xe5\xbd\xab\xef\x7f\xf0\x7f\x7e\xfc\x7f\xfd\x9f\xfc\xbf\x60\xcb\x6f\x77\x44\xd7\xc7\x47\x3e\x3e\xc3\x47\xf8\xa4\x1e\xb5\xc1\x20\x1a\x57\xa0\xd7\x0e\xdc\x1e\x0a\x99\xc1\x6a\x47\x8c\x56\x47\xdb\x57\x56\x47\x7d\xbf\x13\xca\x87\x35\x43\xc3\x7e\xb0\x2d\x60\xd4\x6f\x85\xfd\x6d\x7c\x3d\x08\xd2\x2c\xcc\xc2\x24\xbe\x76\x15\xfe\x11\x7c\x3f\x1a\x5d\x43\x00\x69\x92\x64\x96\xea\xc6\xfd\x20\xdd\x16\x19\x84\x12\xb6\xa2\xa4\xfd\x10\x3a\x62\x14\xb6\x05\x24\x29\x04\xf1\x18\x06\x41\xd6\x5b\x83\x7e\x32\x8c\x33\x18\x24\x61\x9c\x79\xd0\x09\x53\xd1\xce\x92\x74\x8c\x63\xba\x61\x24\x20\x8c\x65\xd8\x11\x10\x66\x1e\x6c\x85\x71\x87\x87\x7b\xb0\x95\xa5\x5d\x09\x72\xb8\x35\x4a\xa2\x61\x5f\x78\x8d\x64\x24\xd2\x28\x18\x77\x25\x34\x87\x83\x81\x48\x1d\x50\xa1\x04\x4d\x46\x67\xa5\x05\x77\x83\xac\x07\xa9\x90\x49\x34\x12\x1d\xc8\x12\x08\x33\x49\x4b\xc9\xb1\xcc\x44\x1f\xb6\xc6\xb0\x3a\x48\x93\xf6\xaa\x14\x51\x77\x95\x96\x0b\xe3\x6e\xd2\x6a\xdc\x60\xe4\xdb\x41\x0c\x5b\x02\xa4\xc8\x20\x90\x10\xc6\xd0\x95\x59\xb0\xb5\xc6\x1b\xd6\x6a\xb5\x3c\xd8\xb8\xfe\xe1\xfa\x06\xff\x79\xf7\xfa\xa7\xf7\x8b\x17\xf8\xcb\xbe\x44\x0a\xb7\xc6\x10\x85\xf1\xc3\x46\x93\x36\x00\x39\xbf\xba\x35\xf6\xc3\xce\x6a\xab\xd5\x5a\x69\xc1\xc7\x05\x56\x7a\xd5\x61\x4c\x08\x89\x4e\x0b\x34\x6f\x0d\x3a\x3b\xc1\x00\xec\x9e\x20\xec\x8d\xcd\x16\xfc\x22\xcc\x7a\xc9\x30\x83\x61\x47\x8c\x68\x25\xa9\xb7\x40\x42\x90\x0a\xe8\x26\xc3\xb8\x83\x48\xa4\x22\xe8\x84\xf1\x36\xc8\xe1\x40\xa4\xb4\x55\xb2\x11\xc4\x1d\x07\x60\x16\x6c\x45\x42\xb6\x1a\xea\x3f\xd5\x54\x9d\xe4\xdf\x80\x0f\xea\xa5\x3a\x51\xf3\xfc\x89\x3a\x53\x73\x35\x85\xfc\x20\xdf\xcb\xf7\xf3\x47\x6a\xae\xde\xe2\x5f\xea\x50\xcd\x41\xcd\xd4\x89\x9a\x81\x3a\xc9\x9f\xab\x97\xf8\x06\xd4\x79\x7e\x90\xef\xe7\xdf\xac\x41\xbe\x4f\xb3\x8f\xd5\x04\xd4\xa9\x9a\xab\xb3\x7c\x5f\xcd\x68\xfe\xa1\x9a\xa8\x33\x35\xcb\x5f\x78\xa0\xce\xd5\x44\x9d\xf3\x20\x86\x95\xff\x4e\x4d\xd4\x5b\x75\x02\xea\x50\x9d\x11\xac\x47\xb8\xc2\x99\x9a\xaa\x29\xcb\x88\x5f\x0f\x4e\x4d\xbd\x86\x3a\x57\x73\x75\x84\x2b\xab\x53\x96\x21\x0f\x1c\xc9\xc9\x1f\xa9\x49\xbe\x97\x3f\xc5\x89\xf9\x0b\x35\xcd\xf7\xf3\xbd\xfc\x05\xae\x34\xcd\x1f\xe5\x8f\xd5\x59\xfe\x22\x7f\xe1\xe0\xb4\xd2\x02\xf5\x1d\xd3\x03\xf9\x9e\x9a\x23\x78\xa2\x7d\xa2\x0e\xd5\x89\x03\x21\xdf\xb3\x78\x13\x42\xc8\x89\x7c\x4f\xcd\x68\xf0\x54\x9d\x6a\xd6\xa8\xf9\x12\xd9\x53\xff\x51\xc7\x5c\x9c\xf6\x1a\xd9\x0f\xf9\x01\xa2\xa3\xde\xa8\x09\xe1\x42\x3f\x8e\x41\x1d\xfe\xcd\xc2\x69\x98\xbd\x97\xef\xe5\xcf\xd4\x89\x3a\xc6\x95\x97\xc9\xa9\xfa\x8b\x43\xda\x24\x7f\x51\x26\x6d\x62\x10\x9d\xe6\xfb\xa0\x5e\xe6\xcf\x18\xc5\x33\x94\x99\xbd\xda\xad\x9a\xb4\xc0\xc8\x59\xfe\xbc\x76\x36\x89\x3b\x8e\x04\xdc\x32\xf5\x46\x1d\xe1\x70\x35\x35\x78\xa3\xf0\xab\x7f\x52\x53\xf5\xa6\x20\x61\xae\x8e\xf9\x20\x2c\x91\xd4\xfc\xeb\x62\xbb\x9e\x10\xee\x24\x34\xea\xb4\x91\xef\xe5\x07\xea\x1c\x65\x80\x65\x9e\xb8\x71\x08\xc8\x1f\xdc\x6a\x7c\x36\xcb\xbf\x2a\xa3\x32\x57\x87\xad\x46\x03\xf5\x20\xf8\xd0\x49\xa0\x9f\x74\xc2\xee\xb8\x38\x51\x12\x9a\x3b\xfa\x74\x0e\xd2\x10\x35\x60\x14\xc4\x2b\xad\x06\xf0\x7f\xe6\xe4\x6a\x00\xc5\x90\x56\xc3\x0c\x51\xdf\xa1\xe4\xab\x53\x46\x94\x99\x3a\x53\x6f\xf4\x03\x7e\xf8\xc2\x0e\x66\x66\x68\x70\x44\xcc\x13\x14\x16\x35\x29\xa4\xfc\x5c\x9d\x20\xfb\x17\xa0\xa8\xb7\x2d\x20\x29\xa3\x1f\x24\x5a\x6a\x96\x3f\x06\x35\xd7\x4c\x99\xe4\x5f\xe1\x28\xde\x53\x75\x98\x3f\xa3\x63\x76\x82\xc7\xc5\x40\x6f\x34\x8c\x91\xf5\xc0\xef\x82\x0f\xfc\xa3\x64\x17\x24\x74\x93\x54\xab\x6a\xd8\xd8\xfc\x04\x58\xb7\xc3\x76\x9a\x0c\x07\xcc\x99\xb0\x0b\x61\x06\xe2\x37\xc3\x20\x82\x45\x63\x0d\xcd\x8e\xe8\x06\xc3\x28\x5b\x01\x9f\x01\x6c\x1b\x70\x49\x1c\x8d\x51\xd3\xc9\x41\x80\x06\x28\x06\x14\x62\x06\x19\xc3\x4e\x2f\x6c\xf7\xe0\xee\x26\x24\x5d\xc8\x7a\x02\xa2\x51\x1f\x36\x6f\x42\x10\xa1\x5e\x1c\x23\xdb\xdb\xa8\x71\x6f\xb1\xb6\x6d\xa7\x22\xc8\x04\xc4\x62\xc7\xdd\x4d\x54\x97\x7a\x2d\xb1\x1b\x4a\x54\xd1\x04\xfe\x56\x17\xc6\xc9\x10\x76\x82\x38\x83\x38\x81\x28\xec\x87\x19\x64\x89\x4b\xe6\x50\x0a\x10\xfd\x41\x36\xd6\x4c\x59\x03\xeb\x90\x2c\x80\x48\x76\x62\x86\xb1\x06\x3b\x69\x98\x09\x48\xc5\xb6\xd8\x1d\x00\xca\x12\x8e\x4a\x21\x1d\xa2\xa2\x86\x5f\x25\x43\xc2\x16\x81\xf7\xd1\xda\xd2\x73\x0f\xa4\x18\x04\x69\x90\x89\x0e\x81\xde\x1a\x43\x3b\xe9\xf7\x83\x16\x7c\x4c\xac\x0f\xfa\x83\x48\x38\xeb\xd3\x79\x97\x9d\xc0\xd3\x7f\x6c\x19\x84\x10\x1a\xc8\x2c\x48\x33\xc9\x6b\xaf\x82\x8f\x5b\xd3\x17\x41\x0c\xc1\x96\x4c\xa2\x61\x26\xc8\xc2\x13\x67\x68\xf8\x20\x15\x03\xa4\x99\xc6\x7f\x09\xcd\x6e\xb1\x24\x98\x85\x5a\x3f\xa2\x15\x52\xc1\x4c\x47\x4e\x7d\x59\xbc\x5b\x29\x2d\xdf\x49\x84\x8c\x7f\x98\x41\x3b\x89\xb3\x20\x8c\xc9\xa7\x48\xba\xd0\x0f\xe4\x43\x68\xf7\x82\x34\x68\x67\x22\x95\x6b\xf0\xe5\x8f\xfe\xf7\xdf\x7d\xfe\x05\x6f\x36\x39\x23\xc1\x60\x40\xde\x00\x63\xf2\xf9\x97\xab\x5f\xfc\xe8\x07\x5a\x08\x08\x7f\x1f\x44\xdc\xd1\x74\x21\xd0\x02\x98\x07\x5b\xc3\x0c\xba\x49\x84\x2e\x91\x66\x65\x92\xf2\x4e\x97\x38\x68\x70\x86\x9d\x30\x8a\xd0\x40\xd7\x52\xc4\x4b\x37\x0c\x55\xae\xbc\x57\xa4\x0f\x42\x16\x59\x0f\xb2\x5e\x90\x41\xb8\x1d\x27\xa9\x20\xdb\xad\x0f\x92\x4f\x92\x7b\x77\x93\x5c\x12\xf3\xba\x93\x86\x23\x41\xd0\x77\x12\xe4\xd4\x96\xd0\x72\xa7\xe9\x48\x85\xd0\x27\x22\x8c\xf5\x7c\x8b\xf0\x50\x8a\xb4\x7a\x20\x37\x09\x41\xad\x82\xd4\x5f\x50\xd9\xe6\xdf\x68\x55\x7a\x68\x6c\x8f\x75\x0b\xf2\x67\xf5\x6e\xc1\xc4\x03\xb4\x54\xa8\x99\x9f\xb0\x5a\x3f\x56\x73\xf2\x06\x1e\xe5\xcf\xf2\xc7\xae\xc2\x2f\x1b\x64\x84\x4f\xaa\xaa\xc0\xe5\x66\xa1\x1b\xd4\xbf\xe5\x7b\x6c\xb4\x1e\x91\xfd\x45\x8d\x55\xa7\x23\xc8\xcc\xe6\x07\xb4\xca\x09\x6a\x41\xd2\x94\xcf\x8d\xce\xb8\x7c\x75\x44\x15\x09\xa7\x15\x4a\x94\x10\x1e\x68\x3a\x90\x8a\x23\xb4\x81\x6c\x2a\x3c\x50\xaf\xd0\x2e\x00\x1a\x3b\x5c\xfb\x35\xfe\x7d\xa6\x26\xf9\x63\xf4\x47\x48\x7b\x23\xe4\x26\x2d\xfe\x2a\x3f\x60\xa6\xa0\x0d\x27\xb7\x02\x8d\xca\xc4\x70\x98\x46\xe2\xda\xa4\x69\xa7\x25\xb3\x93\x3f\xf3\xd8\x26\x1d\x83\x9a\x2d\xc1\x9f\x91\xdc\xcb\x0f\xc8\xe0\xd1\x96\xe4\x07\xf9\xf3\xfc\x6b\xb4\x76\x2b\x15\x5e\xe2\x1a\x80\x58\x92\x89\xde\x27\x12\xf2\xfd\xb2\xd1\x39\xcc\xf7\xe8\xb9\x7a\x45\xa8\xe0\xf3\x27\xc6\xfe\x20\x1b\x4e\xf2\x17\x25\x54\xec\x3b\x62\x37\x32\xe9\x5c\x33\xf4\x4d\x7e\xa0\xde\xf2\x2a\xe7\x2c\x38\xec\x29\xfd\xae\x90\xb4\xaa\x72\xbc\x08\xd3\x37\x6a\x82\x8c\x33\xee\x19\xba\x5d\x33\x84\xcc\xf2\x81\x9e\xc2\xa4\x16\x6d\xf5\x76\x8d\x76\x47\x9d\xab\x59\xfe\x54\x43\x23\xbc\x5f\xe5\x07\x48\x4e\xfe\x48\x4b\x37\x2e\x4a\xb3\x5f\x5b\xa2\xf2\x3d\xa0\x9d\x7a\x4a\xb6\xb9\xba\x1e\x3e\xd2\x2c\xfe\x56\x4d\xb5\x7c\x20\xe9\xc7\x6a\xbe\x00\x0d\x4d\x6a\xe1\xe3\x69\x63\x8b\x86\x1b\x79\x76\xc2\x3b\x0a\x24\x79\x8f\xc8\xba\x13\xc1\xe7\xf4\xfc\x20\x7f\x7e\xa9\x1a\x2f\x58\xe7\xa2\x38\x67\xc1\x7c\xa2\x66\xf8\xaf\xeb\xc1\xa2\x8a\xcf\x7f\x9f\xef\x33\x2e\x73\xc2\xf0\xd4\x19\x62\xbc\xce\x89\x3a\x22\xa9\x3d\xc9\x9f\xe7\xfb\xc4\xa8\xc2\xed\x07\x5a\x4e\x63\x7c\x54\x59\x59\x9d\xa2\xb8\xcc\xd5\x4b\x7e\xa4\xc1\x7e\x89\x22\xdd\x52\x53\xcd\xb6\x32\xae\x85\x6d\x60\xea\x0b\xc1\xd4\xa7\x64\xe2\xda\x8f\x0a\xd9\xe8\xa3\xf2\x01\xa4\x10\x46\x9d\xd7\x70\x62\xca\x27\xf0\x88\x50\x7e\x8d\x90\x81\x04\x76\x9a\x7f\xd5\xc2\xbf\x90\x05\x28\x58\xe4\xf1\xd5\x08\x49\xfe\xb8\x66\x5b\x4b\x36\x49\x33\xb4\xbc\xf0\x91\x9a\x1b\x27\xca\x52\x63\x15\x29\x39\xe3\x64\xb7\x7e\xe0\xb1\xaf\x3a\x07\xd2\x12\xbc\x71\xb4\x23\xe0\xeb\xa8\x8b\x75\x84\x83\x28\xea\x08\x75\x4c\x80\x4e\x2b\xea\x83\x45\x5d\x9d\x14\x41\xce\x5c\x1d\x5b\x71\x9d\x10\x92\xe4\x71\xe6\x8f\x0a\x0b\xa7\x5e\xe6\x07\xc4\x9f\x7d\x77\x0b\xa6\xc6\x63\x9c\x2c\x9a\x3b\xdf\x17\x31\xc6\x93\xfe\x8f\x3f\xd8\x0a\x33\x32\xb7\xf8\x13\xf8\x67\x57\x04\xd9\x30\x15\x68\xca\xc5\x6e\xf6\x81\x1b\x9b\x37\x53\x21\xc3\xdf\x8a\xab\x5d\x09\xfe\xd6\x0a\x7a\x83\xdd\x52\x88\xfc\xc3\x0c\xcd\x16\xe1\x8b\xc9\x19\xc7\xbe\x19\x5f\x3b\xcc\x8a\xa8\x98\x97\xa3\x35\xc8\xa5\x62\x7b\x7a\xf5\xcb\xf7\xaf\xb2\x5b\x2a\xa1\xf9\xde\x8f\xef\x87\x1f\xd2\x64\xf8\xe0\xe7\xe1\x87\xfc\x5c\xeb\xc8\x5b\x19\xec\x24\xe9\x43\xf6\x5a\x6d\x60\xee\x62\x84\x4e\xa7\x31\x96\xff\xac\x8e\xe9\x40\x3c\x31\x5a\x73\xae\xce\xd1\x6d\xce\x9f\x6b\x3c\xf2\x83\x8b\x43\xc4\xfc\x19\xa3\x5a\xe6\x81\x07\x6a\x6a\xc4\xf9\x25\x2b\x01\x8a\x84\xcb\xb0\x16\x63\x32\x46\x8a\xfc\x75\x27\xbc\xc2\xed\x3b\xcb\x5f\xb8\x6a\x5d\xeb\xcd\x97\xc5\x31\x01\x12\x00\xd2\xcd\x36\xc8\xd2\x24\xb0\x28\xb1\x7c\x10\xb2\x8b\xda\x95\xf9\x6b\xe3\x28\x52\x88\x86\xcf\x2c\x5f\x7c\x28\x1c\x50\x6a\xea\x8c\xa7\x7d\xc0\x98\xf3\x4f\x14\x79\xcd\x4d\x08\x53\x58\xe5\x63\x3e\x3f\x24\xc4\x6c\xab\x16\x23\xcc\x33\x63\x57\x2e\xe2\x37\xc5\x6f\x59\x2f\x8c\x7d\x4c\x11\xa0\x9f\x4c\xc2\xda\x4b\x76\xd8\xa3\x1e\x88\xb4\x2d\xe2\x4c\xc2\x28\x4c\x33\x8c\x48\x70\x5f\x50\x6c\xd1\xae\xe1\x3c\xd8\xd8\x24\x1f\x5c\xec\xb6\x85\xe8\xd8\xd7\x98\x70\xa2\xd7\x83\x24\x89\x58\x96\x6e\x70\xdc\x02\x57\xd6\xec\x44\x76\xbb\x24\x0c\x07\x90\x25\x76\x2e\x3a\x69\x34\x0d\xee\x1b\x08\x76\xe4\xd6\xd8\x95\xf8\xa4\xec\x4f\x7a\xb4\x4e\x27\xc8\x02\x72\xc8\xfb\x22\x0b\xe8\x87\x03\xd3\x02\x42\xc0\x69\x32\x48\x52\xce\x25\xd1\x88\x30\x25\x1c\xa4\x91\xe7\x6f\xc9\xed\x29\x9b\x2f\xdc\xbe\x79\xfe\x15\x6e\x33\xed\xc6\x21\x90\x1a\x7f\x84\xf6\x48\x4d\x68\x1c\x5b\x83\x92\xa0\xd0\xd0\x33\x82\xf4\x8a\x5c\xb6\x92\x48\x9e\x93\x4a\x45\x0d\xfa\xd4\x58\x72\x77\x32\xaa\x5b\x5e\xfa\x00\xcd\xab\xd6\x55\xdf\xd5\x7a\x78\xc8\x5d\xbb\x18\x1a\xd7\x8d\x4d\x58\x96\xf1\x39\x52\xf3\xd2\x42\x6a\x52\xac\x41\x39\x1f\x75\xb2\x74\x6e\xc9\xb7\x5d\x38\x3f\xaf\xd4\xbc\x38\x41\xfa\x1c\xbe\xca\x1f\x51\x86\xe1\x3c\x7f\xc6\x18\x9e\x6a\xaf\xf1\x88\xa5\x95\x7d\x8d\x19\xcf\xdb\x57\x93\xf2\x73\x8d\x57\x05\x9f\xfc\xb9\xc1\x87\xb6\x85\x52\x53\x8f\x28\x50\x9f\xab\x33\xb3\x1b\x9c\xf8\x78\x5c\x21\x55\x9d\x92\xe8\x73\x62\x1b\x7c\xd0\x7f\x50\x3e\x96\x74\xa1\x0e\x09\x06\x49\x14\xb6\x43\x21\x29\xea\x2a\xd2\xb8\xb2\x65\xe4\x79\x0d\xea\xb2\xe2\x46\x7b\x9a\xf8\x2d\xc6\xc3\x11\x76\x41\x07\xef\xbc\x8e\x79\x49\xc1\x74\x0b\xee\x0c\x38\xcc\xee\xa6\x49\x9f\x43\xd6\xb8\x83\x19\x4d\x01\xbd\x60\x84\xa1\x65\x98\xa4\x61\x36\xa6\x64\x9e\xc6\x97\x65\xe1\xe7\x62\x2c\x61\x4b\x74\x13\xcc\x77\x86\xa9\xcc\x40\x8a\x36\xc2\xa2\x0c\xa8\x5e\x92\x75\x38\x9a\x8c\x32\x19\xeb\x23\x91\x8e\xed\x84\x50\xba\xaf\xd7\xf8\x20\xfc\x2f\xc2\x46\xc4\x19\xfd\xd2\xc1\xd8\xb5\x9a\xc0\x83\x87\x7f\x4e\xe9\xff\x2f\xca\x83\xeb\xdd\xb3\x8c\x12\xbc\x3e\x9d\xfc\x6b\xf0\xde\x95\x2b\x37\xe9\xf1\x68\xdb\x4f\x85\x14\xe9\x88\x9f\xf2\xc3\x28\x18\x8b\x54\xc2\xb5\x22\x23\xe1\x0d\x46\xde\x68\xdb\x8b\x46\x5e\x57\xd2\x90\x58\xec\x14\x49\x7b\x1c\x1a\x27\x3c\x35\x49\x06\x58\xca\x48\xda\x98\xd5\xb8\x06\x63\xc1\xe3\x87\x4c\x2b\x8d\x73\xd1\xf5\x41\x06\x7d\x01\x81\xb4\xee\x65\x6b\x01\x5d\x1f\xfa\xc1\xae\xd5\x59\x85\x45\x44\xc1\xa0\x24\xfa\x10\x85\xc1\x79\xe1\x6c\x37\xa7\x6b\x70\x1b\x49\x2e\xca\xf3\xab\x1c\xf0\x1d\x8d\xe7\xe9\x10\x5e\x66\xc1\x18\xc2\x78\x21\x83\x04\x41\x17\xf1\xe7\x15\x5a\x2e\xdb\x7c\xfd\x87\x81\xa0\x73\xea\xa6\x68\xb0\x06\x98\x26\xe6\xe8\xbb\xe0\x2f\x0c\x46\x1e\x8c\xb6\x3d\x88\x46\x1e\x29\xed\x07\xa8\x43\x3d\xe2\xa7\x47\x09\x4a\x0f\x3a\x7d\xe7\x28\x04\x51\xd4\xaa\xdb\x09\x1f\xdf\x24\x3b\x4b\xf2\x4a\xcd\xb1\x90\xab\x71\xb2\xe2\x00\x1a\x0b\xc9\x80\xca\x5b\xe7\x83\xfd\x93\x2d\x00\xca\xf4\x76\x9a\xec\x64\x3d\xe4\x22\x0e\x36\x05\x98\xad\xa0\xfd\x10\xf3\xfd\x74\xd2\x9a\x5d\x33\x6f\xc5\xc3\x72\x4b\x26\x02\x62\xbb\x1c\x04\xa9\x14\x1a\x02\xad\x57\xe0\x72\x93\xc1\x86\xd2\xf5\x9c\xca\xc6\xc7\xf5\x83\xd8\xc6\xe0\x13\x87\x8c\x38\x69\xb9\x82\x66\xd8\xa0\x7f\x22\x84\xab\xc8\xf7\x51\x37\xc8\xc8\x64\xdd\xbe\xff\xf1\x3d\xa2\x89\x1d\x20\x07\x9b\xfb\x3d\x21\x85\xb3\xa0\x24\xa4\x21\xe9\x76\x49\x43\xa0\x1b\x86\x76\x55\x8c\xf1\xcc\xf3\x9a\xc6\x5d\xf3\x34\xb4\x0e\x5b\x45\x7e\x48\xfc\x61\x4d\x47\xb2\x9e\xb0\xf2\x69\xc1\x87\x43\x39\x76\x09\x0b\x25\xc8\x87\xe1\x60\x20\x3a\x15\xba\x4c\x82\x44\x57\x2a\xce\xd4\xc4\x3a\xef\x53\x0a\xea\x6b\xc2\xc2\xfa\x18\x59\x57\x49\x70\xd6\xb2\x2a\x49\x6b\x99\xc1\xbb\x40\xfd\x16\xc9\x12\xe3\x03\x4d\x60\x49\x62\x84\xdc\x44\x0e\x28\xe6\xea\x8c\x7e\x01\x56\x5e\x38\x9c\xa1\xc5\x27\x6c\x35\x70\x18\xe6\x70\x28\xa1\x43\xa1\xe1\x99\x36\x7a\x6f\xdd\xe0\x05\x23\x2f\x1a\xfc\xdc\x18\xf8\x19\xda\x26\x0e\x3e\xf0\x11\x9a\xa7\x23\xd7\x3b\x3b\x5d\x60\xa1\x35\xf4\x0b\x4b\x1f\x15\xa1\xb2\x75\xea\xa6\xea\x98\x2c\xdf\x0c\x89\x30\x61\x92\xe1\xf0\x52\xb2\xb5\x0f\x49\x7e\x70\xfe\xf8\x1d\x77\xe2\x0f\x14\xe7\x1d\x19\x97\x5b\xaf\x9c\xbf\x00\xdf\x29\x78\x31\xf2\xcb\x80\x54\xd4\x6d\x4e\xe1\xdd\x6b\x35\xb5\xb1\xde\x45\x8a\x97\xd8\x7e\xac\xe3\xbd\xe5\x3e\xd7\x25\x9e\x2f\xd4\x17\x9c\xa8\x7a\xf6\x2e\x95\xac\x33\x35\x2d\x89\xb3\xeb\x1e\xbd\x64\x97\x31\x7f\x8a\x05\x39\x00\xa0\x0c\x86\x3a\x35\x32\x45\xe5\xac\x0b\x57\x98\xd6\x98\x81\x0b\xc3\x14\xcf\xc9\x30\xf2\x2b\x53\xa5\x2b\x2a\x7c\x8e\x13\xa6\xa6\x8e\x13\xc6\x49\x2a\xaa\xeb\xa9\x93\x0a\x55\x46\x84\x2a\xc6\x84\x46\xce\xd5\xcc\x2b\x25\x36\x8b\x50\xeb\x4c\xcd\x4b\x60\x38\xe0\xfa\x9f\x59\x99\xa5\x1a\x80\xc5\x77\x89\xe1\x61\x99\x40\x42\x70\x37\x18\xb1\x22\xdf\x48\xe5\x2f\x9b\x69\xcc\x1f\x97\x53\x05\xc8\x9b\x42\x05\x2f\x5d\x7f\xa9\xb1\x42\x98\x0c\xca\x04\x9c\x76\xbf\xb4\x52\x00\x0a\x27\xf6\x38\xfb\x56\xa8\x29\x02\x54\x97\x56\x76\x2d\x19\xa8\x43\x07\x5e\x41\xa7\xce\xc1\x18\x47\xdc\xae\x30\x29\x68\xe1\xc3\xf9\x27\x7e\x51\x17\xc1\x96\x65\x2d\x7f\x86\xb2\x6b\xd7\x52\xa7\x97\x1d\x2e\x8f\xb3\x22\x25\x91\x7c\x0b\x28\x5c\x26\xbf\xa4\xe7\x5f\xc0\xd5\x05\xdb\x59\xb3\x93\x4b\x83\x5f\xdc\x55\xc7\xb6\xaa\x19\x9b\xd6\x8b\x32\xb5\xce\x46\xff\x17\x42\x2b\xd1\x68\x33\x4c\x05\x8d\x06\xca\x3e\xa5\xb2\xdd\xd0\x9c\xd0\xd6\x96\x79\x0d\x38\x8b\xb8\x80\x2a\xe5\xa3\xe9\x64\x7a\x4b\xa3\x9a\x19\xd4\xcf\x40\x98\x2f\x69\xd2\xbe\x16\x68\x60\xac\x70\x06\xe5\xdd\xcf\xcb\x46\x43\x27\x65\x41\xfd\xbb\xc9\x7e\xb0\xf2\x7e\x97\x04\x8a\x0e\xad\x50\x1a\x29\x99\xa1\xf5\xc9\x25\x3b\xd7\xe0\xde\x21\xdf\x16\x0c\x29\xcc\x70\x62\x0b\x13\xe6\xd8\xb0\xab\x85\xe5\x21\xfd\xbb\x17\x90\xa7\xec\x0c\x97\xb5\xa0\x74\x89\x4f\xec\x66\x57\x57\xdf\x5f\xfd\x80\x1c\x9c\xdd\xae\x2c\xb9\xe3\xf7\xb2\x24\xc5\xbe\x1e\xd9\x0b\xa8\x9c\x24\xb2\x1d\x21\xe2\x32\xec\x66\xd9\xaf\x63\x0d\xe5\x3a\xd6\x2b\x10\x72\xc8\x80\xf5\xe9\x18\x7d\xf6\x18\xbd\xf1\x6e\x92\xea\x88\xcb\x01\x87\xfd\x37\x56\x01\x25\x5d\x48\x62\x41\x10\xd9\x63\x0b\xe3\x0e\x95\x08\x45\x9c\x45\x63\x0f\x44\xd0\xee\x41\x18\x67\x09\x95\x3d\x0b\x34\x8c\x7f\xf5\x27\x47\x52\x4b\xb5\x08\x6b\x69\x67\x4b\xad\x2c\x55\xb9\x4b\xde\x4f\xd5\xc3\x70\x4b\x0b\xe5\xb7\x6f\x71\xaf\xf3\xc7\xc6\x31\x7a\x07\xc7\x40\x9f\xd0\x32\xb6\x4c\x84\x4d\xb3\xd5\x4d\xd5\x35\xb4\x4b\x0e\x9b\xdd\x62\x35\xc3\x1d\xae\xb5\xde\x13\x8f\x0f\xc6\xd7\xac\xd4\xb5\x6f\x73\x56\xa9\x41\xcc\xde\xcd\xcf\xe1\x68\xe0\x42\x9b\x5b\xd4\xbf\x26\x9e\x6b\x5b\x27\x8e\x6d\x5d\xf1\x6c\xd3\xc1\xe2\x19\x3e\x52\x33\x75\xa6\xf5\x1a\xf8\x16\xe3\x92\x67\x77\xf9\x36\xeb\x44\xa1\x2d\x8f\x81\x9b\x87\xe1\x0d\xb0\x78\x96\x95\x16\xf2\x86\x12\xa2\x87\x6a\xa6\xdd\x29\x76\x24\xc8\xbf\xe3\xa2\xc6\xa1\xb1\x05\x97\xe5\x49\x1b\x0d\xa7\xe1\x8f\x42\x68\x4c\x5a\xe0\xdf\x59\xa2\xa3\xb0\xbf\xbf\x77\xe7\xf6\x0a\x07\x1d\xdd\x30\xde\x16\x29\xf5\x7c\xd0\x21\xe1\x43\xcd\xb1\x9b\x89\x51\xb3\x9e\x05\x30\x6c\xf7\xd6\x88\x14\x74\x01\xbd\x6a\xd3\x16\x64\xe3\x81\xf0\xe0\xe6\xdd\xfb\x7c\xd0\x6e\x7e\x76\xeb\x06\x24\x29\xf4\x65\x27\x91\xfc\x48\x86\xdb\x31\xe5\xdc\xdd\xc9\xa4\x50\x32\xc9\x2e\xda\xdd\xcd\xd5\xcd\x9b\xab\x1b\x9b\xd4\x48\x24\x3d\x37\x06\xc2\x27\xa6\x37\x82\x4b\xcc\x43\x69\x4a\xeb\xd8\x6e\x62\x0e\xeb\x9f\xd5\x3c\x7f\x6c\x2d\x29\x1d\x56\xdb\x70\x72\x68\x45\xdc\xf0\x21\xdf\x63\xa3\x50\x34\xaa\x98\x5c\x71\xe1\x8f\x2f\xb8\x00\x8b\x0e\x17\x37\x44\xe1\xa2\xaf\xd4\x8c\xa4\x82\x93\x8a\xbc\xf0\xda\x42\xaa\x99\xaa\xa7\x33\x75\x5e\x6a\xec\xd1\xe6\xcc\xf5\x7c\x3c\x66\xa3\x23\x3b\xc8\x5f\xdb\x34\xa5\x66\xea\x15\x69\x0c\x14\x25\xca\x1d\x16\x03\x89\xef\x7c\x24\x34\x2f\x6a\x17\x20\xd4\x90\xb7\x96\xf7\x1e\xff\xbc\xdc\x75\xff\xb6\x92\xd5\x2f\x55\x58\x8b\x4a\xbd\x69\x6d\x33\x52\x8a\x32\x6c\x76\xcc\xed\x20\x05\x1f\xa2\x24\xe8\xb0\xb4\x91\x61\xc2\xdd\xf7\x48\x86\x29\xee\x77\x44\xdb\x23\x2b\xd3\xee\x89\xf6\xc3\x05\x29\xd6\x4d\x3e\xb6\x2d\x06\x53\xe8\x1d\xdd\x53\xd9\x0b\xe2\x6d\xd1\xd1\x19\x1a\x84\x06\x3e\xa4\xa2\x8b\xcd\x2b\x9c\x70\x4c\xd3\x24\x6d\xd5\x77\x45\x99\x93\xe0\x15\x32\x47\xf6\x50\xb4\xb1\x05\x25\xcc\x8c\x00\xa2\x79\x67\x3d\xf4\x66\x41\x00\xcb\xb6\xc0\x23\x2f\xd8\x4a\xab\xeb\x30\x96\x68\x35\xa5\xd3\x39\x37\x08\x1a\xa8\x55\xb9\x9d\xd5\x88\x6a\xb5\xcc\xce\x5d\x55\x73\x35\xf5\xd9\x61\x5d\xd2\x4c\x69\xfa\xb2\xa8\x4a\x98\xef\xe5\xdf\x94\x42\x93\x2a\xd2\x6c\x78\x08\x1f\xea\x0e\xd4\xa7\x0a\x2b\xd3\x2f\xb9\x88\xd8\x5a\xda\x18\xe6\xb0\x47\x4d\x74\x9c\xb6\x67\x87\x39\x3d\x5e\x1a\x9f\x29\x49\x0d\xa6\x5a\x3c\x64\xd2\xc3\x70\x80\xff\x62\x03\x63\xe4\xec\x06\xbe\x27\x1d\x83\x02\x41\xed\x6c\xb0\x19\x44\x43\xc1\x99\x24\x49\x8f\x65\x26\x06\xe4\x09\xec\x0a\x09\xcd\x40\x97\x23\x42\x4a\xcc\xe0\x94\x15\x94\x31\x27\xe2\x72\x7a\x9a\x6c\x3f\xd3\xf7\x09\xa6\x50\x19\xa2\x8e\x84\x38\xe8\xe3\x8a\xd1\xa8\xff\x20\x1a\x39\xf3\x1e\xc4\x62\x47\x87\x05\x4c\x61\x95\x20\x94\x40\xc4\x5a\x1a\xd2\xa9\xe5\x8f\xd3\x99\x3c\xac\x18\xa1\xc1\x54\x19\x43\x2f\x75\xad\x47\x3b\x4d\x41\xd6\xee\x61\xd5\x28\x13\x03\xcc\xcb\xb5\xa3\x61\x87\xc5\x79\xa1\xd9\x48\x63\xe5\xe6\x7e\x0b\x8f\xb0\xd2\xa3\x76\x77\x53\x37\x31\xc5\x49\xb6\x90\x59\xd5\xd8\x77\x1d\x87\x12\x1d\x55\xe6\x33\x95\xb1\x06\x59\xa9\x1e\x8b\x5a\x69\xf3\xa6\x09\x47\x3b\x7d\xc8\x12\x0c\x12\x0c\x7d\xcc\xca\x3a\x60\x1c\xbd\x10\xb9\x1b\x9b\xcb\x11\xba\xc7\xd9\x35\x96\x09\x93\x23\x1e\xa4\xc9\x88\x7b\xc2\xa5\xc9\x6f\x66\x09\xc4\x62\x37\x33\xbb\x50\x6e\x6e\x32\x26\xd3\x74\x54\x51\x49\x01\xa5\xca\x99\xc2\xf6\x13\x33\xc7\x43\x89\x56\xb3\xa5\xfb\x8b\x74\xea\x98\xd6\xd7\x4c\x96\xb2\x04\x5b\x26\x10\x66\x20\x45\x24\xda\x19\xb5\x77\x6d\x8b\xac\x27\x52\x56\x46\x88\xe2\xc6\xa6\xad\x01\x3b\xa7\x86\x75\x45\xa9\x68\x49\x07\x6f\xaf\x72\xf4\x28\x2e\x71\x12\x56\x6a\xca\x19\x86\x73\x52\xeb\x73\x75\xcc\x01\x3a\x57\x6e\xa8\x05\xe1\x29\x59\x3b\xfc\xb3\x59\xf4\x01\xeb\x76\x96\xa2\xeb\x93\x55\xda\x69\xb1\xd2\x74\x05\xd8\x76\x9d\x90\xff\x7b\xe8\x34\x96\x30\xf6\x95\xe6\x92\xef\x71\xc0\xb4\x5d\xb4\xcd\xaa\x13\x6d\x65\x19\xc9\x77\x3a\x6f\xea\xf0\x12\xde\xfd\x9e\xc3\x52\x26\x7e\xe6\x1c\xc4\x9a\xa6\x54\x9e\x5e\x99\x51\x39\x98\x75\x0b\xea\xa1\x0b\x39\xc6\x57\x36\x37\xc6\xed\x24\xf9\x0b\x6c\xad\xa2\xc7\x7a\x0e\xfa\xc0\x87\x6e\x07\x00\x77\x37\xe3\x1e\xd4\xb7\x57\x2c\x3b\xd4\x8b\x51\x44\xa5\xff\x0b\x37\xf1\xee\xa6\x67\x1b\x76\x2b\xd1\xc3\x41\xfe\xbc\xec\x30\x1c\x2c\x9e\xfc\x9a\x35\x8a\x28\xc5\x66\xb6\x8a\x06\x92\x4b\xba\x16\x9a\xdc\x90\x46\xb1\x77\x55\x5f\xd4\xe9\x8a\x4b\x03\x25\x42\x81\x37\x61\xe3\xfb\x53\xaa\xfe\x68\xfb\xd7\xa6\x3a\xc2\x7a\xab\xb7\x89\xa1\x14\xed\x49\xa6\xb3\x60\x4f\x13\xc6\x1b\x64\x72\x46\x8c\xca\x54\x1d\xd9\xee\xb9\x53\xbb\xdd\xea\x54\xe3\x75\x49\x5e\xd2\xb4\x02\x9a\x7c\x38\x8a\xcd\xd4\x64\x24\xab\xf0\xf3\xc7\x6c\x86\xf5\x1a\xf9\x63\xaf\x26\x91\x79\xc4\x8f\x28\xda\xa2\xdc\x54\x0b\xd4\x5f\x99\xb8\xfa\x2a\x73\xf9\x1c\x2d\x23\x7e\x19\x01\xe4\x7e\xfc\x9e\xa1\xe5\x07\x7c\x6a\x5e\x12\x8f\xdc\xe4\xaa\x9d\x31\xd5\x8e\x44\xc1\x19\xe4\x1a\x69\x48\xdf\xff\x75\xb2\x85\x16\xf4\xd7\x54\x71\xd6\x35\x20\x72\x3a\xb5\x56\xaf\x14\xe7\xc8\x70\x76\x30\x2b\xd2\x1e\xa6\x29\xa5\x0c\x9c\x2a\xcc\x7b\xda\x80\xa0\xe2\xde\x09\x42\x5d\xe2\x2d\x41\x32\x76\xa4\xd0\xe6\x7c\x1d\xa8\x05\xf7\xdc\x61\x14\x60\x71\x21\x88\xa2\xa5\x24\xad\x69\xac\xb0\xe8\x48\x91\x86\x41\x84\xa8\x94\x4c\xb4\x63\x85\x93\x18\x2f\xde\xa4\x04\x8c\x6d\x32\xb7\x57\x60\xef\xae\x26\x8e\x4b\x81\x15\xda\xb4\x11\xf9\x03\xe9\x24\xca\x45\x5a\xcf\xd0\xd1\xf9\x8e\xd3\x56\x9b\x7e\xd6\xcd\xa5\x0b\xfa\xd0\x5c\x01\xe0\xd4\xd8\x09\x49\x9e\xd1\x50\xcb\xb3\x59\x9a\xcb\x46\xc2\x5e\xeb\x3e\xb0\x02\x9f\x45\x24\xce\xb5\x78\x95\x2e\xca\x70\x29\x88\x4e\xe9\xa5\x1d\x47\x7f\x35\x2a\x7e\x31\xba\x33\x5d\xac\x45\xfe\xc0\x04\x5e\xd6\x06\x15\x87\xe0\xc0\x76\xdf\xd9\x76\x8b\x32\x57\x8a\x06\x0a\xe3\x62\x23\xda\x1c\x3e\x95\xb8\x53\xd1\xdd\xde\x82\x4a\x26\x93\x47\xfd\x20\xd4\x09\x58\x6a\x0f\xa6\x44\xd2\x8c\xdb\x30\xee\x6e\xba\x9b\x54\xdf\xfa\xab\x13\x8f\xb5\x1b\x85\xa7\x28\x15\xb2\x4d\x41\x9b\x6e\x7a\xa0\x13\xa4\x1f\xb6\x83\x41\xd0\xa6\x36\x89\x2e\xdc\xfb\xe8\xde\x2d\x92\x3e\xec\x5e\x0a\x13\x5f\xb6\x65\xa8\x65\x92\xab\xf3\x5c\x57\x82\x26\xdf\x33\xc8\xb8\x5b\x60\x55\x8e\xe5\x6a\x3b\x0a\xa4\x5c\xa5\x5e\xb8\x55\xd9\xf9\xe5\x2a\x47\x71\xab\xbc\x08\xb9\xe7\x74\xe4\xf8\xb6\x4d\x13\xff\x1f\x74\xfa\x20\x45\x96\x45\x62\x85\x72\x05\xb1\xb0\xd1\x9e\x2e\xd5\xd2\xd2\xe8\x94\xf5\xc6\x03\x91\x8e\x42\x99\xa4\x7c\xb2\x76\x7a\x22\x86\x87\x22\x8d\x45\x04\x32\xc3\xe6\x74\x89\x1d\x58\x49\xc4\x0d\x55\x2d\xb8\x13\x71\xf1\x16\x0b\xe7\xf8\x84\xef\xb6\xe9\x88\xa1\x65\xc8\xdb\x8a\x1e\x1a\xea\x06\x1d\xaa\x91\x63\xbd\xcc\xb4\x05\x32\xea\xe6\x88\x7d\xe7\xf4\xa7\x6a\x75\xca\x49\x41\x7d\x21\xc8\x39\x64\xe5\xae\xdc\xfd\xda\x16\x25\xa7\x11\x9c\xb9\xae\x66\x25\xa6\x9b\xb2\xda\x57\xf6\xca\x53\x93\x9d\x2d\x9c\x47\xc6\xf7\x9d\x59\xaf\xed\xa2\xae\x40\x9a\x63\xbd\x64\x1f\xd4\xb7\xb6\xe0\xe6\x46\x91\x07\x18\xcd\x1a\x15\x53\x74\x71\x6b\x69\x35\xd8\xd0\x91\x33\x2e\x10\x06\xa6\x73\xe4\x02\x1f\x77\x6a\x76\x9f\x40\xfe\x42\x1d\x21\xe3\x40\x4d\xd1\xb0\x31\x9d\x14\x88\xe6\xcf\x6c\x2e\x86\xb5\x40\xfe\x68\xa1\x46\xd9\xe2\x7d\xd0\xfe\xaa\x3d\x8c\xa5\xf1\x7c\xf1\x8f\xb3\xa2\xe5\xd9\x94\x08\xf9\x57\x8d\xf4\xcc\x11\x02\x46\x1e\xd5\x0b\x4d\xb4\xca\xa7\xbc\x65\xba\x95\xb2\xb4\xb7\x1a\x69\x53\xa3\x35\x77\x67\x7d\x73\x50\x42\x9d\x4d\x4b\xba\xb6\x21\xb0\x1f\xb4\x7b\x61\xcc\x62\x46\x39\xec\x4a\x6e\x6e\x8d\xaa\x8b\x1b\x9b\x68\x53\x9c\xfe\x8c\x6a\x92\x1c\xa3\x47\x7b\x5f\x09\x85\xbb\x1f\x50\x54\x94\x89\xfe\x20\x49\x83\x34\x8c\xc6\xd0\xe4\xa9\xfc\x2a\x85\x40\xc2\x43\x5c\x6c\x97\xcc\x1e\xba\x49\xc5\x2f\x67\xa5\x15\x0f\xa2\x40\x66\x0e\x5e\x78\x92\xd0\x5a\xe2\xa5\x1a\xd3\xd5\xd4\x2c\xc2\x32\x0f\xee\x6e\xae\x10\x0e\xa6\x3b\x86\x89\x33\x28\xd2\x7c\xc4\x21\x8c\xb7\xa5\xb5\x63\x3d\x91\x0a\x9a\x94\x8a\x7e\x32\xb2\x47\x1f\xdb\x67\xa1\x69\x46\x6b\x7b\xab\xcd\x29\x5d\xa1\xd2\x3a\xcc\xa3\xc8\x8c\x1b\x3d\x80\xb9\xde\x1f\x4a\xca\x35\xca\xde\x30\x83\x4e\xb2\x13\xbb\xc9\x21\x1e\x62\x36\xa3\xa0\x2d\x74\x5a\x8c\xcc\xfd\xac\x8f\xea\xb2\x96\x18\xa7\x22\x21\x4e\xb6\xc7\xe8\x06\xe7\xfe\xab\x35\x2d\x0b\x4d\x8e\xec\x2d\x9e\xea\x5b\x00\x67\xf9\xb3\x92\xcb\x33\x55\x6f\x4b\xf9\x45\x7e\x50\x49\x00\x5a\xd1\xd0\x86\x6b\x59\xd5\x73\xbe\x98\xe1\x76\xcf\x2a\xe9\xb1\x53\x93\xc2\xd2\xc2\x9f\xef\x9b\x1a\x99\x7a\xed\x1c\xad\x8a\x08\xe9\xe0\x51\x8b\x91\xce\xbf\x17\x92\xa4\x1f\x2c\xc3\x6b\xb2\xe2\x95\x0d\x27\xdf\x67\x70\xc9\xa4\xe3\xab\xc3\xa4\x52\x8f\x3d\xd9\xc0\xa9\x2e\x32\x5c\x5c\x86\x63\x79\x5c\x56\x23\xac\x24\x37\x1d\x4d\x7c\xea\x55\x8b\xfa\xdc\x4c\xac\xb7\xca\x56\xc0\x75\x8b\x65\x99\x65\xb6\x37\xe7\x80\x46\x9d\x38\x4b\x36\xcb\x17\x6b\x18\x6b\x75\x48\x72\x30\x2d\xd2\xcd\xdc\xa3\x6d\x7c\x20\x33\x1b\x75\xf2\xbf\xf0\xc3\xfc\x1b\x56\xe4\x27\xbc\x9c\x73\x6f\x16\x75\x9d\x09\x24\xb9\xff\xc6\x64\xf0\x58\xec\xad\x58\x96\x24\x6a\x72\x51\x4f\x86\xc9\x19\x57\xaf\xe2\x4c\xeb\xf9\xa8\x03\x46\x3c\x24\x6a\x56\x39\x23\xfc\x99\x80\x9f\x51\xcd\x32\xec\x6f\xff\xff\x9a\x3c\x54\x55\xc5\x98\x2b\xfe\x69\xb0\xa3\xbf\x32\x50\xa3\x41\x9b\xa5\x16\xce\xad\x24\xc9\xd0\x91\xc0\xd1\x84\x0d\x5e\xea\xcb\xb2\x80\x12\x66\x59\x62\x15\xe3\xd8\xd5\x75\x7c\xb8\xdd\xd6\xba\x28\x91\x22\x1b\x0e\x80\xbf\x66\x40\x16\x14\xc3\x53\xd2\xb9\x8c\x09\xea\xac\xa0\x9d\x85\xa3\x80\x7a\xc5\x0a\xb5\x45\xab\x9a\x96\x32\xe8\x08\x3b\x88\xa8\x73\x57\x45\xb3\x20\x18\xb5\x16\x9d\x69\xdd\xbd\x2a\x41\x0a\xa1\x07\x39\x2b\x62\xd0\xff\x53\xbc\x7d\x6a\x9f\xd8\x3e\x34\x5a\x33\xd6\xcd\x97\x3c\xa2\x97\x48\x32\x3c\x05\xc1\xa9\xc0\x11\xc4\x85\xdb\xd7\x3f\x59\x7f\x80\xa8\xdc\x26\xa4\xcc\x1b\x6a\x6e\x73\x15\x30\xea\x6e\x7e\x89\xa0\xa2\x64\x7b\x5b\x6b\x42\x5a\x50\xa3\x4b\x96\xa3\x1f\xc4\xb8\x29\x69\x12\x45\x08\xc5\x83\x50\x77\x8f\xa7\xc9\x76\x1a\xf4\x41\x66\xc9\xc0\x76\xdb\x32\xd5\x96\x0e\xb3\x5f\xe5\xf6\x5e\xf4\xfe\xd6\xf4\x95\x4f\xf2\xc8\xc2\xcc\xcc\x6f\x66\xe9\x30\xe6\x66\x11\xe9\xc1\x6f\x44\x7f\xe8\xe3\xb7\x27\x78\xdc\x4a\x6b\xa9\xe6\xf6\xec\x51\x58\x50\xe1\x2c\xb8\xc5\xa7\x2b\x6e\x83\xef\xc8\xa5\xc1\xb4\xc5\x9f\xb3\xe0\x82\x97\x4f\xe6\x59\xbf\x81\xe6\xe6\x4d\xcc\x31\x33\xaf\x62\x4b\x98\x8d\x3a\xcb\xc6\x74\x79\x9d\xfa\x7b\xa8\xc1\xca\x77\x0e\x8c\x16\x7a\x43\xea\xfd\x72\xc3\xd3\x5c\x6c\x88\xc3\xb9\xb6\x12\x72\xac\x66\xa8\x76\xfe\x68\xc0\x6a\x6f\xd4\xaa\x18\xf7\x7a\x97\xc9\xc3\x94\x6c\x0a\xc5\x6c\x4b\xac\x00\x45\x73\x15\x45\xc4\x17\x3e\x6a\x8f\xde\xb2\x0c\x45\x99\xe8\x89\x3a\xa6\xf4\xe1\x61\xb5\x53\x64\x99\x42\xe7\x26\x13\x53\xec\x9d\x2e\x03\x00\x17\x59\x59\x53\x45\xa9\x30\xa5\xc5\xf1\xb8\x6e\x75\xcc\x9f\xd1\x01\x47\xc0\x94\x04\xac\x5c\x4a\xa9\xb3\x92\x25\xda\x7e\xba\xac\x50\x5e\x1a\x65\x34\xb0\x4e\xab\x61\x02\xc5\x49\x22\x91\x69\x39\xf5\xec\xf5\xd1\x83\xa5\x5c\xa5\xbb\x92\x64\x7f\xbc\x85\x4d\x2d\xf2\x40\x06\x28\x3b\xd9\x87\xe5\x9e\x39\x47\xc3\xa8\xd9\x72\xfe\xb3\x4d\x39\x30\x8c\xb3\x7e\x10\x6f\xc9\x4c\x9d\x2e\x76\xed\x34\xeb\x10\x28\x7a\xd2\xf8\x62\xa6\x83\x09\x39\x08\xaf\xc8\xc8\xbb\xbd\xa7\x24\xee\xa6\xaf\x89\x30\xb0\x57\x30\x74\x55\x4c\xd3\x3f\x75\xaf\x42\x9a\x96\xaf\x53\x45\x5f\xaf\xd0\x5c\xd2\x38\xcc\x9c\x4c\x5b\xe1\x4f\x39\x16\x59\xcd\x1c\x72\x70\xc3\x9c\x2f\x64\x54\x44\xd9\x31\xc9\xd4\xfa\xba\x66\x15\x41\x29\x1a\x71\x1a\x23\xdd\xe0\x4c\xa7\x66\xdf\xe8\x54\x00\xfb\x14\x97\x69\x4d\xb4\xf4\x9a\xef\xef\x66\xed\xab\x4a\xb5\x6a\xf2\xeb\x74\x6a\x59\xbd\x39\x24\x2f\xaa\x56\xf7\x2d\x34\x97\xca\xff\x39\xd7\x3f\x75\xf5\x13\xca\x47\x62\x7a\x51\x03\xe2\xa2\x13\xea\x56\xfd\x09\x45\x93\x37\x09\x7f\x2b\x7c\xb4\x6b\xe8\xae\xf0\x3d\xaf\x2c\x31\x76\xa9\x08\x81\xd6\x20\x18\x66\x49\xf1\xad\x09\x0f\xe2\x20\x0b\x47\xc2\x83\x2c\x49\x22\x5d\xdf\xe6\x47\xe0\x9b\xcc\x45\x98\xb4\xb3\x48\xae\xc1\xfa\x2f\xef\x7f\xf0\xe0\xd6\x9d\x8f\x1e\x7c\xba\x7e\xef\xd6\x3f\xac\x3f\xd0\x2d\xed\xa6\xf1\x5c\xec\x66\xef\xaf\x7e\xe0\xc1\x2f\x3f\xbe\x47\xa3\x3e\xbe\x77\xf3\xd3\x3b\xbf\xf8\xf8\xde\x8d\xeb\xf7\xaf\xd3\xc0\x5d\x7d\x9b\xa3\x59\x5c\x38\xc4\x9e\x2e\x73\x35\xa5\xea\xfe\xd8\x8f\x20\xad\xb4\xe0\xb6\xce\x8f\xa0\x35\xd3\x73\xb0\x1e\xc6\x25\x7d\x46\xd3\xd3\x97\x2e\xb1\x97\xc8\xa7\x17\xb4\x58\xf1\x59\xa0\xd6\xc2\xbd\x1d\xbc\x88\x88\xe6\x5f\xae\xee\xea\x3f\x98\x01\xc4\x0b\xaa\xe2\x9b\xdb\x8a\xba\xff\xec\x01\x9a\xfc\xae\x1e\x45\x9c\xf4\x2d\x03\xf9\xf3\x1e\xdd\x80\x2a\x30\xcc\x4d\xf8\x2c\x2e\x33\x07\x82\x68\x27\x18\x4b\xdb\xb7\xbf\x35\xd6\x7f\x5e\xed\xca\xa2\xd7\x84\x37\x7d\xae\x5e\xd6\x7c\x13\xa5\x7a\xb1\xeb\x92\x02\x86\xd9\xef\x65\xdf\x0d\xb8\x78\xff\x69\xe3\x4d\x2a\x64\x52\x2b\x00\x46\x41\xed\x5d\x70\x55\xf1\x42\xc1\xd0\x00\xac\x6c\x5c\x70\xed\x71\xc2\xe3\x2a\xbd\x93\xee\xd9\x9f\x57\x8c\x01\x15\x52\xcc\x27\x8f\xf2\x03\x56\x21\x75\x29\x17\x82\x6b\xd2\x60\x05\x44\x6a\xad\xd0\xe4\x7b\x85\x70\xe1\x73\xfb\x25\x82\xca\xc7\x75\x26\x3a\x20\x21\x63\x39\xa5\xfb\xc5\x68\x36\xde\x5d\xd4\xd4\xec\x32\x49\xd3\x1d\xc6\x4e\x37\xc4\xb4\x10\x39\x5d\x22\x5a\xc6\x3f\x4d\x03\x17\xa4\x4c\xae\xab\xbe\xd9\xdd\xb9\x9c\xef\x0a\xe9\x0d\x91\x89\x36\x65\x1d\xd1\x6b\x6c\xa8\xef\xf4\x1a\x38\x56\x37\xa2\x4d\x49\x27\xd3\x47\x0f\xd8\x48\x2d\xe9\x3c\x6f\xdc\xcb\x3a\xc9\x30\x5b\x83\x3b\x3f\x6f\x14\x09\x33\xfe\xc0\xd2\x04\xac\xe5\x3a\xa2\x9c\x99\xbd\x8d\xcf\xb6\x7c\xae\x8e\xd6\xd0\xf5\xfb\x03\x71\x68\xdd\x24\x74\xda\x49\x7f\x20\xb0\x04\xfe\xa9\xc8\x86\x69\x0c\xed\xa4\x23\xe0\x4a\x6b\xd1\x97\x35\x05\x69\xdd\x26\x87\x69\x37\xdb\x46\x7e\xa0\x6f\xc9\x3f\xd5\x39\x72\x74\x93\x8e\x48\xbe\xd4\x1b\x12\x30\x26\xea\x8a\x43\xc1\xed\xf5\xf5\x1b\xf0\xe9\xfa\x87\x77\xee\xdc\x87\xeb\xb7\x6f\xc0\xbd\xfb\xd7\x3f\xbd\x0f\x9f\xac\xc3\x9d\xdb\x1f\xad\xc3\xf5\x9b\xd7\x6f\xdd\x6e\xfd\x6d\x34\xbe\x13\x64\x00\x80\xdb\xa8\xd2\x52\x81\xf1\xa5\xfe\x6e\x4d\x6c\x03\xa0\xe2\x3e\x10\x76\x9b\xf4\x05\x7e\x0f\xa6\xcc\xa3\xf7\xae\xfe\xc4\x54\x73\xdd\xcc\xaa\x96\x80\xc5\xf6\xa3\x3f\xaa\x3f\x93\xdf\xc4\x95\x75\xf2\x0e\x9d\xf8\xc0\x0d\x19\x74\xd2\xe9\x1d\x7b\x99\x8d\x42\x58\xd8\x17\xab\xfc\xb4\x6b\xb6\x7c\x5f\x88\x94\xc6\x15\xf8\x19\x7c\x84\x94\xfd\x0c\x1f\xf0\xc7\x71\xa8\x15\x0b\x13\x74\xfa\x26\xbd\xce\x04\xf0\x63\xdd\xd1\xeb\xb6\x32\x1b\x6b\x81\xb1\x21\x24\xd8\x1a\x21\x3d\xcd\xae\xf7\x20\x25\xee\x99\x84\x60\x63\xe1\x06\x26\x61\xb0\x0c\x47\x46\xca\x5f\xfc\xf4\x41\x71\xaa\x4d\x49\xb2\xd4\xab\x44\x6e\xfb\x9f\x35\xde\xc5\x58\x76\xc6\xed\x7d\xa5\x77\x69\x21\x3e\x73\xef\x92\x9c\x71\x31\xab\xe8\x33\x6c\xd8\x57\xe6\x16\x8e\xc9\x8f\x1f\x21\xed\x25\x7a\xbe\x2e\x7f\x88\xcb\xf8\xd1\xd6\xc9\x79\xa9\xbd\xcc\xd9\xf7\xbb\x8f\xd4\x68\xfc\xf7\x00\x3a\xf1\x27\xd4\xe8\x52\x00\x00")
 
 func usageTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -104,7 +104,7 @@ func usageTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "usage.txt", size: 3694, mode: os.FileMode(436), modTime: time.Unix(1449659275, 0)}
+	info := bindataFileInfo{name: "usage.txt", size: 21224, mode: os.FileMode(436), modTime: time.Unix(1792369312, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,204 @@
+package fsextender
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Block device ioctls from linux/fs.h
+// ioctl блочных устройств из linux/fs.h
+var (
+	blk_SSZGET     = ioc(0, 0x12, 104, 0)
+	blk_PBSZGET    = ioc(0, 0x12, 123, 0)
+	blk_GETSIZE64  = ioc(ioc_READ, 0x12, 114, unsafe.Sizeof(uintptr(0)))
+	blockDevCached = &blockDevCache{}
+)
+
+type blockDevGeometry struct {
+	Size               uint64
+	SectorSizeLogical  uint64
+	SectorSizePhysical uint64
+}
+
+/*
+Cache of device numbers and geometry. It works during scan only: execution of plan changes sizes of devices.
+
+Кеш номеров и геометрии устройств. Работает только во время сканирования: выполнение плана меняет размеры устройств.
+*/
+type blockDevCache struct {
+	mutex      sync.Mutex
+	enabled    bool
+	majorMinor map[string][2]int
+	geometry   map[string]blockDevGeometry
+}
+
+// Start cache for scan. Every scan start with empty cache.
+// Включает кеш на время сканирования. Каждое сканирование начинается с пустого кеша.
+func (this *blockDevCache) Begin() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.enabled = true
+	this.majorMinor = make(map[string][2]int)
+	this.geometry = make(map[string]blockDevGeometry)
+}
+
+func (this *blockDevCache) End() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.enabled = false
+	this.majorMinor = nil
+	this.geometry = nil
+}
+
+func (this *blockDevCache) getMajorMinor(path string) (mm [2]int, ok bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	mm, ok = this.majorMinor[path]
+	return
+}
+
+func (this *blockDevCache) setMajorMinor(path string, mm [2]int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.enabled {
+		this.majorMinor[path] = mm
+	}
+}
+
+func (this *blockDevCache) getGeometry(path string) (geometry blockDevGeometry, ok bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	geometry, ok = this.geometry[path]
+	return
+}
+
+func (this *blockDevCache) setGeometry(path string, geometry blockDevGeometry) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.enabled {
+		this.geometry[path] = geometry
+	}
+}
+
+// Major and minor numbers from dev_t, as gnu_dev_major and gnu_dev_minor.
+// Старший и младший номера из dev_t, как gnu_dev_major и gnu_dev_minor.
+func devMajorMinor(dev uint64) (major, minor int) {
+	major = int((dev>>8)&0xfff | (dev>>32)&0xfffff000)
+	minor = int(dev&0xff | (dev>>12)&0xffffff00)
+	return
+}
+
+// Return major and minor numbers of device. Symlinks followed. 0, 0 if path isn't device.
+// Возвращает старший и младший номера устройства. Ссылки разыменовываются. 0, 0 если путь - не устройство.
+func getMajorMinor(path string) (major, minor int) {
+	if mm, ok := blockDevCached.getMajorMinor(path); ok {
+		return mm[0], mm[1]
+	}
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return 0, 0
+	}
+	major, minor = devMajorMinor(uint64(stat.Rdev))
+	blockDevCached.setMajorMinor(path, [2]int{major, minor})
+	return major, minor
+}
+
+// Read size and sector sizes of block device by ioctls.
+// Читает размер и размеры секторов блочного устройства через ioctl.
+func getBlockDevGeometry(path string) (geometry blockDevGeometry, err error) {
+	if geometry, ok := blockDevCached.getGeometry(path); ok {
+		return geometry, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return geometry, err
+	}
+	defer f.Close()
+
+	if err = ioctl(f, blk_GETSIZE64, unsafe.Pointer(&geometry.Size)); err != nil {
+		return geometry, err
+	}
+	var sectorSize uint32
+	if err = ioctl(f, blk_SSZGET, unsafe.Pointer(&sectorSize)); err != nil {
+		return geometry, err
+	}
+	geometry.SectorSizeLogical = uint64(sectorSize)
+	if err = ioctl(f, blk_PBSZGET, unsafe.Pointer(&sectorSize)); err != nil {
+		return geometry, err
+	}
+	geometry.SectorSizePhysical = uint64(sectorSize)
+	blockDevCached.setGeometry(path, geometry)
+	return geometry, nil
+}
+
+// Return size of block device as it showed by kernel (in bytes)
+func getDiskSize(path string) uint64 {
+	for i := 0; i < TRY_COUNT; i++ {
+		if i > 0 {
+			log.Println("Try to read devsize once more: ", path)
+			time.Sleep(time.Second)
+		}
+		geometry, err := getBlockDevGeometry(path)
+		if err == nil {
+			return geometry.Size
+		}
+		log.Println("Can't read block device size:", path, err)
+	}
+	return 0
+}
+
+// Device name from uevent of sysfs directory: /sys/class/block/sda1/uevent contain DEVNAME=sda1
+// Имя устройства из uevent папки sysfs: /sys/class/block/sda1/uevent содержит DEVNAME=sda1
+func sysDeviceName(sysDir string) string {
+	uevent, err := ioutil.ReadFile(filepath.Join(sysDir, "uevent"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(uevent), "\n") {
+		if strings.HasPrefix(line, "DEVNAME=") {
+			return "/dev/" + strings.TrimPrefix(line, "DEVNAME=")
+		}
+	}
+	return ""
+}
+
+// Device by major and minor numbers from sysfs.
+// Устройство по номерам из sysfs.
+func deviceByMajorMinor(major, minor int) string {
+	return sysDeviceName(fmt.Sprintf("/sys/dev/block/%v:%v", major, minor))
+}
+
+// All block devices of system.
+// Все блочные устройства системы.
+func listBlockDevices() (res []string) {
+	dirs, _ := filepath.Glob("/sys/class/block/*")
+	for _, dir := range dirs {
+		if device := sysDeviceName(dir); device != "" {
+			res = append(res, device)
+		}
+	}
+	return res
+}
+
+// Disks, which have partitions.
+// Диски, на которых есть разделы.
+func listPartitionedDisks() (res []string) {
+	dirs, _ := filepath.Glob("/sys/class/block/*")
+	for _, dir := range dirs {
+		if partitions, _ := filepath.Glob(filepath.Join(dir, "*", "partition")); len(partitions) == 0 {
+			continue
+		}
+		if device := sysDeviceName(dir); device != "" {
+			res = append(res, device)
+		}
+	}
+	return res
+}
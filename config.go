@@ -0,0 +1,264 @@
+package fsextender
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Default path of config file.
+// Путь к файлу настроек по умолчанию.
+const config_DEFAULT_PATH = "/etc/fsextender.conf"
+
+// Extend policy of mount point.
+// Правила расширения точки монтирования.
+type mountPolicy struct {
+	MountPoint    string
+	Filter        string                   // Filter of disks, as --filter. Фильтр дисков, как --filter
+	TargetSize    uint64                   // Max size of filesystem. 0 - unlimited. Максимальный размер файловой системы. 0 - без ограничений
+	VGReserve     uint64                   // Free space, which stay in volume group. Свободное место, которое остается в группе томов
+	Layers        map[storageItemType]bool // Layers, which can be extended. nil - all. Слои, которые можно расширять. nil - все
+	NewPartitions bool                     // Allow create new partitions. Разрешено создание новых разделов
+	LoopAllocate  bool                     // Allocate space for growth of loop file. Выделять место при росте файла loop-устройства
+	Umount        bool                     // Allow umount of filesystems, which grow offline only. Разрешено отмонтирование файловых систем, которые растут только offline
+}
+
+/*
+Config file. Keys before first section are defaults for all mount points. Every section is mount point:
+
+	# comment
+	filter = LVM_ALREADY_PLACED
+
+	[/home]
+	target-size = 100G
+	vg-reserve = 10G
+	layers = partition,pv,vg,lv,fs
+	new-partitions = no
+	loop-allocate = yes
+	umount = no
+
+Файл настроек. Параметры до первой секции - значения по умолчанию для всех точек монтирования. Каждая секция - точка
+монтирования.
+*/
+type config struct {
+	Default mountPolicy
+	Mounts  []mountPolicy
+}
+
+// Names of layers for policies and command line. Also accepted names of types without prefix type_.
+// Имена слоев для правил и командной строки. Также принимаются имена типов без префикса type_.
+var layerNames = map[string][]storageItemType{
+	"fs":        {type_FS},
+	"disk":      {type_DISK},
+	"partition": {type_PARTITION, type_PARTITION_NEW},
+	"pv":        {type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW},
+	"vg":        {type_LVM_GROUP},
+	"lv":        {type_LVM_LV},
+	"thin_pool": {type_LVM_THIN_POOL},
+	"loop":      {type_LOOP},
+	"swap":      {type_SWAP},
+	"dm":        {type_DM},
+}
+
+func newConfig() config {
+	return config{Default: mountPolicy{Filter: FILTER_LVM_ALREADY_PLACED, NewPartitions: true}}
+}
+
+/*
+Read config file. If file doesn't exist and mustExist is false - return default config.
+
+Читает файл настроек. Если файла нет и mustExist == false - возвращает настройки по умолчанию.
+*/
+func readConfig(path string, mustExist bool) (conf config, err error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && !mustExist {
+		return newConfig(), nil
+	}
+	if err != nil {
+		return conf, err
+	}
+	defer f.Close()
+	return parseConfig(f)
+}
+
+func parseConfig(r io.Reader) (conf config, err error) {
+	conf = newConfig()
+	policy := &conf.Default
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return conf, fmt.Errorf("Config line %v: bad section: %v", lineNum, line)
+			}
+			mountPoint := strings.TrimSpace(line[1 : len(line)-1])
+			if !filepath.IsAbs(mountPoint) {
+				return conf, fmt.Errorf("Config line %v: mount point must be absolute path: %v", lineNum, mountPoint)
+			}
+			conf.Mounts = append(conf.Mounts, conf.Default)
+			policy = &conf.Mounts[len(conf.Mounts)-1]
+			policy.MountPoint = filepath.Clean(mountPoint)
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			return conf, fmt.Errorf("Config line %v: expected key = value: %v", lineNum, line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+		switch key {
+		case "filter":
+			policy.Filter = value
+		case "target-size":
+			policy.TargetSize, err = parseSize(value)
+		case "vg-reserve":
+			policy.VGReserve, err = parseSize(value)
+		case "layers":
+			policy.Layers, err = parseLayers(value)
+		case "new-partitions":
+			policy.NewPartitions, err = strconv.ParseBool(parseBoolAlias(value))
+		case "loop-allocate":
+			policy.LoopAllocate, err = strconv.ParseBool(parseBoolAlias(value))
+		case "umount":
+			policy.Umount, err = strconv.ParseBool(parseBoolAlias(value))
+		default:
+			err = fmt.Errorf("unknown key '%v'", key)
+		}
+		if err != nil {
+			return conf, fmt.Errorf("Config line %v: %v", lineNum, err)
+		}
+	}
+	return conf, scanner.Err()
+}
+
+// Return policy of mount point. Path inside mount point gets policy of the mount point. If config doesn't contain
+// the mount point - return default policy.
+// Возвращает правила точки монтирования. Путь внутри точки монтирования получает ее правила. Если в настройках ее
+// нет - возвращает правила по умолчанию.
+func (conf config) policy(mountPoint string) mountPolicy {
+	mountPoint = filepath.Clean(mountPoint)
+	for _, policy := range conf.Mounts {
+		if policy.MountPoint == mountPoint {
+			return policy
+		}
+	}
+	if mount, _, err := resolvePath(mountPoint); err == nil && mount.MountPoint != mountPoint {
+		for _, policy := range conf.Mounts {
+			if policy.MountPoint == mount.MountPoint {
+				policy.MountPoint = mountPoint
+				return policy
+			}
+		}
+	}
+	policy := conf.Default
+	policy.MountPoint = mountPoint
+	return policy
+}
+
+// Targets for --all: mount points from config or all mounted filesystems if config hasn't mount points.
+// Цели для --all: точки монтирования из настроек или все смонтированные файловые системы, если в настройках их нет.
+func (conf config) targets() []mountPolicy {
+	if len(conf.Mounts) > 0 {
+		return conf.Mounts
+	}
+	var res []mountPolicy
+	for _, mountPoint := range getMountedFilesystems() {
+		res = append(res, conf.policy(mountPoint))
+	}
+	return res
+}
+
+func (policy mountPolicy) planOptions() planOptions {
+	return planOptions{
+		Filter:            policy.Filter,
+		TargetSize:        policy.TargetSize,
+		VGReserve:         policy.VGReserve,
+		Layers:            policy.Layers,
+		DenyNewPartitions: !policy.NewPartitions,
+		LoopAllocate:      policy.LoopAllocate,
+		AllowUmount:       policy.Umount,
+	}
+}
+
+/*
+Parse size with optional binary suffix: 1024, 100M, 1.5G, 2TiB. Empty string or 0 - 0.
+
+Разбирает размер с необязательным двоичным суффиксом: 1024, 100M, 1.5G, 2TiB. Пустая строка или 0 - 0.
+*/
+func parseSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.ToUpper(s)
+	num := strings.TrimRight(s, "KMGTPEIB")
+	suffix := strings.TrimSuffix(strings.TrimSuffix(s[len(num):], "B"), "I")
+	var multiplier float64 = 1
+	if suffix != "" {
+		pos := strings.Index("KMGTPE", suffix)
+		if len(suffix) != 1 || pos == -1 {
+			return 0, fmt.Errorf("bad size suffix: %v", s)
+		}
+		multiplier = float64(uint64(1) << (10 * uint(pos+1)))
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("bad size: %v", s)
+	}
+	return uint64(value * multiplier), nil
+}
+
+/*
+Parse comma separated list of layers, for example "partition,pv". Names from layerNames or type names without prefix
+type_ (lvm_lv, partition_new).
+
+Разбирает список слоев через запятую, например "partition,pv". Имена из layerNames или имена типов без префикса type_
+(lvm_lv, partition_new).
+*/
+func parseLayers(s string) (map[storageItemType]bool, error) {
+	res := make(map[storageItemType]bool)
+layersLoop:
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if types, ok := layerNames[name]; ok {
+			for _, t := range types {
+				res[t] = true
+			}
+			continue
+		}
+		for t := type_FS; t < type_SKIP; t++ {
+			if strings.ToLower(strings.TrimPrefix(t.String(), "type_")) == name {
+				res[t] = true
+				continue layersLoop
+			}
+		}
+		return nil, fmt.Errorf("unknown layer: %v", name)
+	}
+	return res, nil
+}
+
+// strconv.ParseBool doesn't know yes/no/on/off.
+// strconv.ParseBool не знает yes/no/on/off.
+func parseBoolAlias(s string) string {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return "true"
+	case "no", "off":
+		return "false"
+	default:
+		return s
+	}
+}
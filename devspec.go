@@ -0,0 +1,120 @@
+package fsextender
+
+import (
+	"fmt"
+	"github.com/rekby/fsextender/probe"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Device specs of fstab and udev directories with links for them.
+// Обозначения устройств из fstab и папки udev со ссылками для них.
+var deviceSpecLinks = map[string]string{
+	"UUID":      "/dev/disk/by-uuid",
+	"LABEL":     "/dev/disk/by-label",
+	"PARTUUID":  "/dev/disk/by-partuuid",
+	"PARTLABEL": "/dev/disk/by-partlabel",
+}
+
+// Parse spec as UUID=01234567-89ab-cdef-0123-456789abcdef or LABEL="my data". ok == false if it isn't device spec.
+// Разбирает обозначение вида UUID=01234567-89ab-cdef-0123-456789abcdef или LABEL="my data". ok == false, если это не
+// обозначение устройства.
+func parseDeviceSpec(spec string) (key, value string, ok bool) {
+	index := strings.Index(spec, "=")
+	if index <= 0 {
+		return "", "", false
+	}
+	key = strings.ToUpper(spec[:index])
+	if _, ok = deviceSpecLinks[key]; !ok {
+		return "", "", false
+	}
+	value = spec[index+1:]
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	if value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// Encode name for udev link as udev does: space -> \x20, / -> \x2f. UTF-8 is kept.
+// Кодирует имя для ссылки udev как udev: пробел -> \x20, / -> \x2f. UTF-8 сохраняется.
+func udevEncode(s string) string {
+	res := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c >= 0x80,
+			strings.IndexByte("#+-.:=@_", c) != -1:
+			res = append(res, c)
+		default:
+			res = append(res, fmt.Sprintf(`\x%02x`, c)...)
+		}
+	}
+	return string(res)
+}
+
+/*
+Resolve start point from command line. Device spec (UUID=, LABEL=, PARTUUID=, PARTLABEL=) and links to block devices
+(/dev/disk/by-id/...) are resolved to canonical device, other paths are returned as absolute path.
+
+Разбирает точку старта из командной строки. Обозначения устройств (UUID=, LABEL=, PARTUUID=, PARTLABEL=) и ссылки на
+блочные устройства (/dev/disk/by-id/...) заменяются каноническим устройством, остальные пути возвращаются абсолютными.
+*/
+func resolveStartPoint(spec string) (string, error) {
+	if key, value, ok := parseDeviceSpec(spec); ok {
+		return findDeviceBySpec(key, value)
+	}
+	path, err := filepath.Abs(spec)
+	if err != nil {
+		return "", err
+	}
+	if stat, err := os.Stat(path); err == nil && stat.Mode()&os.ModeDevice != 0 && stat.Mode()&os.ModeCharDevice == 0 {
+		return readLink(path)
+	}
+	return path, nil
+}
+
+/*
+Find device by spec. First try link of udev, if it doesn't exist (no udev, old link) - probe devices.
+
+Ищет устройство по обозначению. Сначала пробует ссылку udev, если ее нет (нет udev, старая ссылка) - проверяет
+устройства.
+*/
+func findDeviceBySpec(key, value string) (string, error) {
+	link := filepath.Join(deviceSpecLinks[key], udevEncode(value))
+	if _, err := os.Stat(link); err == nil {
+		return readLink(link)
+	}
+
+	switch key {
+	case "UUID", "LABEL":
+		for _, device := range listBlockDevices() {
+			res, err := probe.ProbeFile(device)
+			if err != nil {
+				continue
+			}
+			if key == "UUID" && strings.EqualFold(res.UUID, value) || key == "LABEL" && res.Label == value {
+				return device, nil
+			}
+		}
+	case "PARTUUID", "PARTLABEL":
+		for _, diskPath := range listPartitionedDisks() {
+			disk, err := readDiskInfo(diskPath)
+			if err != nil {
+				continue
+			}
+			for _, part := range disk.Partitions {
+				if part.Number == 0 {
+					continue
+				}
+				if key == "PARTUUID" && strings.EqualFold(part.UUID, value) || key == "PARTLABEL" && part.Label == value {
+					return part.Path, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("Can't find device: %v=%v", key, value)
+}
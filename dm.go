@@ -0,0 +1,378 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Device mapper device grows by 4KiB blocks, as usual block of filesystem.
+// Устройство device mapper растет блоками по 4KiB, как обычный блок файловой системы.
+const dm_ALIGN = 4096
+
+const dm_SECTOR = 512
+
+var errDMTableChanged = errors.New("Table of device mapper device changed after scan")
+
+// Line of device mapper table. Start, length and offsets in 512-byte sectors.
+// Строка таблицы device mapper. Начало, длина и смещения в 512-байтных секторах.
+type dmTarget struct {
+	Start     uint64
+	Length    uint64
+	Type      string     // linear, striped or other (can't be extended). linear, striped или другой (не расширяется)
+	ChunkSize uint64     `json:",omitempty"` // Size of stripe chunk (striped). Размер блока полосы (striped)
+	Devices   []dmDevice `json:",omitempty"` // Source devices (linear, striped). Исходные устройства (linear, striped)
+}
+
+type dmDevice struct {
+	Major  int
+	Minor  int
+	Offset uint64
+}
+
+// Device mapper device from sysfs.
+// Устройство device mapper из sysfs.
+type dmInfo struct {
+	Name string
+	UUID string
+}
+
+func readDMInfo(major, minor int) (dm dmInfo, ok bool) {
+	return readDMInfoDir(fmt.Sprintf("/sys/dev/block/%v:%v/dm", major, minor))
+}
+
+// dir - dm directory of device in sysfs: /sys/block/dm-0/dm
+// dir - папка dm устройства в sysfs: /sys/block/dm-0/dm
+func readDMInfoDir(dir string) (dm dmInfo, ok bool) {
+	read := func(name string) string {
+		content, _ := ioutil.ReadFile(filepath.Join(dir, name))
+		return strings.TrimSpace(string(content))
+	}
+	dm.Name = read("name")
+	dm.UUID = read("uuid")
+	return dm, dm.Name != ""
+}
+
+/*
+Device mapper device, which is not managed by LVM or kpartx (they have own layers). UUID of LVM LV begins with LVM-,
+partitions of kpartx - with part.
+
+Устройство device mapper, которое не управляется LVM или kpartx (у них свои слои). UUID LVM LV начинается с LVM-,
+разделов kpartx - с part.
+*/
+func dmGeneric(dm dmInfo) bool {
+	return !strings.HasPrefix(dm.UUID, "LVM-") && !strings.HasPrefix(dm.UUID, "part")
+}
+
+// Device mapper device with the name exists.
+// Устройство device mapper с таким именем существует.
+func dmExists(name string) bool {
+	_, _, err := cmd("dmsetup", "info", name)
+	return err == nil
+}
+
+func readDMTable(name string) ([]dmTarget, error) {
+	res, stderr, err := cmd("dmsetup", "table", name)
+	if err != nil {
+		return nil, fmt.Errorf("dmsetup table %v: %v (%v)", name, err, strings.TrimSpace(stderr))
+	}
+	return parseDMTable(res)
+}
+
+/*
+Parse table of device mapper device, as dmsetup table show it:
+0 2097152 linear 8:17 2048
+2097152 4194304 striped 2 128 8:33 2048 8:49 2048
+
+Разбирает таблицу устройства device mapper в формате dmsetup table.
+*/
+func parseDMTable(s string) (res []dmTarget, err error) {
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("Bad line of device mapper table: %v", line)
+		}
+		var target dmTarget
+		target.Start, err = strconv.ParseUint(fields[0], 10, 64)
+		if err == nil {
+			target.Length, err = strconv.ParseUint(fields[1], 10, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Bad line of device mapper table: %v", line)
+		}
+		target.Type = fields[2]
+		args := fields[3:]
+		switch target.Type {
+		case "linear":
+			if len(args) != 2 {
+				return nil, fmt.Errorf("Bad linear target: %v", line)
+			}
+		case "striped":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("Bad striped target: %v", line)
+			}
+			stripes, err := strconv.Atoi(args[0])
+			if err == nil {
+				target.ChunkSize, err = strconv.ParseUint(args[1], 10, 64)
+			}
+			if err != nil || stripes < 1 || target.ChunkSize == 0 || len(args) != 2+2*stripes {
+				return nil, fmt.Errorf("Bad striped target: %v", line)
+			}
+			args = args[2:]
+		default:
+			// Other targets can't be extended, their arguments don't need
+			// Остальные цели не расширяются, их аргументы не нужны
+			args = nil
+		}
+		for i := 0; i+1 < len(args); i += 2 {
+			var dev dmDevice
+			if _, err = fmt.Sscanf(args[i], "%d:%d", &dev.Major, &dev.Minor); err != nil {
+				return nil, fmt.Errorf("Bad device of device mapper target: %v", line)
+			}
+			if dev.Offset, err = strconv.ParseUint(args[i+1], 10, 64); err != nil {
+				return nil, fmt.Errorf("Bad offset of device mapper target: %v", line)
+			}
+			target.Devices = append(target.Devices, dev)
+		}
+		res = append(res, target)
+	}
+	return res, nil
+}
+
+// Format table for dmsetup load.
+// Форматирует таблицу для dmsetup load.
+func formatDMTable(table []dmTarget) string {
+	var lines []string
+	for _, target := range table {
+		fields := []string{formatUInt(target.Start), formatUInt(target.Length), target.Type}
+		if target.Type == "striped" {
+			fields = append(fields, strconv.Itoa(len(target.Devices)), formatUInt(target.ChunkSize))
+		}
+		for _, dev := range target.Devices {
+			fields = append(fields, fmt.Sprintf("%v:%v", dev.Major, dev.Minor), formatUInt(dev.Offset))
+		}
+		lines = append(lines, strings.Join(fields, " "))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Table can be extended if it contains only linear and striped targets.
+// Таблицу можно расширить, если в ней только цели linear и striped.
+func dmCheckTable(table []dmTarget) error {
+	if len(table) == 0 {
+		return errors.New("Empty device mapper table")
+	}
+	for _, target := range table {
+		if target.Type != "linear" && target.Type != "striped" {
+			return fmt.Errorf("Device mapper target %v can't be extended", target.Type)
+		}
+	}
+	return nil
+}
+
+/*
+Free space of source device after end of segment (sectors), in bytes. Space is limited by segments of other device
+mapper devices (holders of the source) on the same device. toEnd - segment can take space up to end of device, so it
+can use growth of the source.
+
+Свободное место исходного устройства после конца сегмента (в секторах), в байтах. Место ограничено сегментами других
+устройств device mapper (держателей источника) на том же устройстве. toEnd - сегмент может занять место до конца
+устройства, так что ему доступен рост источника.
+*/
+func dmDeviceFree(dev dmDevice, end uint64, devSize uint64, holders [][]dmTarget) (free uint64, toEnd bool) {
+	limit := devSize / dm_SECTOR
+	toEnd = true
+	for _, table := range holders {
+		for _, target := range table {
+			if dmCheckTable([]dmTarget{target}) != nil {
+				// Unknown layout of other target - don't touch the device
+				// Неизвестное расположение другой цели - не трогаем устройство
+				return 0, false
+			}
+			for _, other := range target.Devices {
+				if other.Major == dev.Major && other.Minor == dev.Minor && other.Offset >= end && other.Offset < limit {
+					limit = other.Offset
+					toEnd = false
+				}
+			}
+		}
+	}
+	if limit <= end {
+		return 0, toEnd
+	}
+	return (limit - end) * dm_SECTOR, toEnd
+}
+
+/*
+Extend last target of table by free space of its devices. Striped target grows on all stripes equally by whole chunks.
+limit - max growth, 0 - unlimited. Return new table and growth in bytes.
+
+Расширяет последнюю цель таблицы за счет свободного места ее устройств. Цель striped растет на всех полосах одинаково,
+целыми блоками. limit - максимальный рост, 0 - без ограничений. Возвращает новую таблицу и рост в байтах.
+*/
+func dmGrowTable(table []dmTarget, devFree []uint64, limit uint64) (res []dmTarget, growth uint64) {
+	res = make([]dmTarget, len(table))
+	copy(res, table)
+	if len(res) == 0 || len(devFree) == 0 {
+		return res, 0
+	}
+	last := &res[len(res)-1]
+	stripes := uint64(len(devFree))
+	var unit uint64 = dm_ALIGN
+	if last.Type == "striped" {
+		unit = last.ChunkSize * dm_SECTOR
+	}
+	perDevice := devFree[0]
+	for _, free := range devFree {
+		if free < perDevice {
+			perDevice = free
+		}
+	}
+	if limit > 0 && perDevice*stripes > limit {
+		perDevice = limit / stripes
+	}
+	perDevice = perDevice / unit * unit
+	growth = perDevice * stripes
+	last.Length += growth / dm_SECTOR
+	return res, growth
+}
+
+// Holders of block device: device mapper devices, which use it.
+// Держатели блочного устройства: устройства device mapper, которые его используют.
+func dmHolders(dev dmDevice) (res [][]dmTarget, err error) {
+	dirs, _ := filepath.Glob(fmt.Sprintf("/sys/dev/block/%v:%v/holders/*", dev.Major, dev.Minor))
+	for _, dir := range dirs {
+		dm, ok := readDMInfoDir(filepath.Join(dir, "dm"))
+		if !ok {
+			// Holder isn't device mapper (md), its layout is unknown
+			// Держатель не device mapper (md), его расположение неизвестно
+			res = append(res, []dmTarget{{Type: filepath.Base(dir)}})
+			continue
+		}
+		table, err := readDMTable(dm.Name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, table)
+	}
+	return res, nil
+}
+
+/*
+Source devices of last target of table and their free space after the target. Free space of partitioned device isn't
+used: it belongs to partitions.
+
+Исходные устройства последней цели таблицы и их свободное место после нее. Свободное место устройства с разделами не
+используется: оно принадлежит разделам.
+*/
+func dmDevicesFree(table []dmTarget) (paths []string, free []uint64, toEnd []bool, err error) {
+	last := table[len(table)-1]
+	stripeLength := last.Length / uint64(len(last.Devices))
+	for _, dev := range last.Devices {
+		path := deviceByMajorMinor(dev.Major, dev.Minor)
+		if path == "" {
+			return nil, nil, nil, fmt.Errorf("Can't find source device %v:%v", dev.Major, dev.Minor)
+		}
+		end := dev.Offset + stripeLength
+		size := getDiskSize(path)
+		if partitions, _ := filepath.Glob(fmt.Sprintf("/sys/dev/block/%v:%v/*/partition", dev.Major, dev.Minor)); len(partitions) > 0 {
+			size = 0
+		}
+		holders, err := dmHolders(dev)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		devFree, devToEnd := dmDeviceFree(dev, end, size, holders)
+		paths = append(paths, path)
+		free = append(free, devFree)
+		toEnd = append(toEnd, devToEnd && size > 0)
+	}
+	return paths, free, toEnd, nil
+}
+
+/*
+Free space of striped device: every stripe grows by own free space and growth of its source in plan.
+parents - items of plan, which provide free space to the device.
+
+Свободное место устройства с чередованием: каждая полоса растет за счет своего свободного места и роста ее источника
+в плане. parents - элементы плана, которые предоставляют место устройству.
+*/
+func dmStripedFreeSpace(item storageItem, parents []storageItem) uint64 {
+	devFree := make([]uint64, len(item.DMDevFree))
+	copy(devFree, item.DMDevFree)
+	for _, parent := range parents {
+		for i, path := range item.DMDevices {
+			if parent.Path == path {
+				devFree[i] += parent.FreeSpace
+			}
+		}
+	}
+	_, growth := dmGrowTable(item.DMTable, devFree, 0)
+	return growth
+}
+
+/*
+Load extended table of device mapper device and activate it with suspend/resume. Return new size of device.
+
+Загружает расширенную таблицу устройства device mapper и активирует ее через suspend/resume. Возвращает новый размер
+устройства.
+*/
+func dmExtend(item storageItem) (newSize uint64, err error) {
+	table, err := readDMTable(item.DMName)
+	if err != nil {
+		return 0, err
+	}
+	if !reflect.DeepEqual(table, item.DMTable) {
+		return 0, errDMTableChanged
+	}
+	_, devFree, _, err := dmDevicesFree(table)
+	if err != nil {
+		return 0, err
+	}
+	newTable, growth := dmGrowTable(table, devFree, item.FreeSpace)
+	if growth == 0 {
+		return 0, errors.New("No free space on source devices")
+	}
+
+	if err = dmLoad(item.DMName, newTable); err != nil {
+		return 0, err
+	}
+	return getDiskSize(item.Path), nil
+}
+
+/*
+Load new table of device mapper device and activate it with suspend/resume.
+
+Загружает новую таблицу устройства device mapper и активирует ее через suspend/resume.
+*/
+func dmLoad(name string, table []dmTarget) error {
+	f, err := ioutil.TempFile("", "fsextender-dm-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(formatDMTable(table))
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if _, stderr, err := cmd("dmsetup", "load", name, f.Name()); err != nil {
+		return fmt.Errorf("dmsetup load: %v (%v)", err, strings.TrimSpace(stderr))
+	}
+	if _, stderr, err := cmd("dmsetup", "suspend", name); err != nil {
+		cmd("dmsetup", "clear", name)
+		return fmt.Errorf("dmsetup suspend: %v (%v)", err, strings.TrimSpace(stderr))
+	}
+	if _, stderr, err := cmd("dmsetup", "resume", name); err != nil {
+		return fmt.Errorf("ATTENTION!!! Device is suspended, dmsetup resume: %v (%v)", err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
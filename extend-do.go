@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"github.com/rekby/fsextender/Godeps/_workspace/src/github.com/rekby/gpt"
 	"github.com/rekby/fsextender/Godeps/_workspace/src/github.com/rekby/mbr"
+	"github.com/rekby/fsextender/probe"
 	"io/ioutil"
 	"log"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,13 +19,15 @@ const MAX_UINT32 = 4294967295
 const TRY_COUNT = 5 // Retry operations if it can and first is fail. For example - fast change LVM not always succesfully
 // and need retry after few seconds.
 
+// Options of execute plan.
+// Параметры выполнения плана.
+type doOptions struct {
+	Jobs          int    // Count of steps, which can be executed concurrently. Количество шагов, выполняемых параллельно
+	ResizeBackend string // Backend of filesystem resize: backend_AUTO, backend_NATIVE, backend_TOOLS. Способ изменения размера файловой системы
+}
+
 func extendPrint(plan []storageItem) {
-	addSize := make([]uint64, len(plan))
-	for i, item := range plan {
-		item.FreeSpace += addSize[i]
-		if item.Child != -1 {
-			addSize[item.Child] += item.FreeSpace
-		}
+	for i, item := range planPropagateFreeSpace(plan) {
 		fmt.Print(strconv.Itoa(i) + ": ")
 		switch item.Type {
 		case type_PARTITION:
@@ -38,416 +44,872 @@ func extendPrint(plan []storageItem) {
 	}
 }
 
-func extendDo(plan []storageItem) (needReboot bool) {
+/*
+Execute plan. Steps, which doesn't depend from each other, executed concurrently by options.Jobs workers. Step depend from its
+parents (steps, which provide free space to it). Steps, which touch same disk or volume group, executed serially.
+
+Выполняет план. Шаги, не зависящие друг от друга, выполняются параллельно в jobs потоков. Шаг зависит от своих
+родителей (шагов, которые предоставляют ему свободное место). Шаги, затрагивающие один диск или группу томов,
+выполняются последовательно.
+*/
+func extendDo(plan []storageItem, options doOptions) (needReboot bool) {
+	jobs := options.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	// Swap must be turned off before underlying layers change
+	// Swap нужно отключить до изменения нижележащих слоев
+	swapPrepare(plan)
+
+	// Count of parents, which doesn't done yet
+	// Количество еще не выполненных родителей
+	waitParents := make([]int, len(plan))
+	for _, item := range plan {
+		if item.Child != -1 {
+			waitParents[item.Child]++
+		}
+	}
+	var ready []int
 	for i := range plan {
-		log.Println("DO ", strconv.Itoa(i)+":", plan[i])
-		item := &plan[i]
-		switch item.Type {
-		case type_PARTITION:
-			oldKernelSize := getDiskSize(item.Path)
-			oldFreeSpace := item.FreeSpace
-			switch item.Partition.Disk.PartTable {
-			case "msdos":
-				if item.Partition.Number > 4 {
-					log.Println("WARNING: Can't work with partition number > 4 in msdos partition table. Skip it.")
-					continue
-				}
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDONLY|os.O_SYNC, 0)
-				if err != nil {
-					log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partTable, err := mbr.Read(diskIO)
+		if waitParents[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	type stepResult struct {
+		index      int
+		needReboot bool
+	}
+	results := make(chan stepResult)
+	locks := newResourceLocks()
+	running := 0
+	for done := 0; done < len(plan); done++ {
+		// Start ready steps in plan order. With one job it is same order as plan.
+		// Запускаем готовые шаги в порядке плана. При одном потоке порядок совпадает с планом.
+		sort.Ints(ready)
+		for running < jobs && len(ready) > 0 {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+			go func(i int) {
+				resources := extendDoResources(plan, i)
+				locks.Lock(resources)
+				defer locks.Unlock(resources)
+				results <- stepResult{index: i, needReboot: extendDoItem(plan, i, options)}
+			}(i)
+		}
+
+		res := <-results
+		running--
+		if res.needReboot {
+			needReboot = true
+		}
+		if child := plan[res.index].Child; child != -1 {
+			waitParents[child]--
+			if waitParents[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	return needReboot
+}
+
+// Execute one step of plan.
+// Выполняет один шаг плана.
+func extendDoItem(plan []storageItem, i int, options doOptions) (needReboot bool) {
+	log.Println("DO ", strconv.Itoa(i)+":", plan[i])
+	item := &plan[i]
+	switch item.Type {
+	case type_PARTITION:
+		oldKernelSize := getDiskSize(item.Path)
+		oldFreeSpace := item.FreeSpace
+		switch item.Partition.Disk.PartTable {
+		case "msdos":
+			if item.Partition.Number > 4 {
+				log.Println("WARNING: Can't work with partition number > 4 in msdos partition table. Skip it.")
+				return
+			}
+			diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDONLY|os.O_SYNC, 0)
+			if err != nil {
+				log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
 				diskIO.Close()
-				if err != nil {
-					log.Println("Can't read partition table: ", item.Partition.Disk.Path, err)
-					continue
-				}
-				newSize := item.Size + item.FreeSpace
-				sectorSize := newSize / item.Partition.Disk.SectorSizeLogical
-				if sectorSize > MAX_UINT32 {
-					// Тут возможно окргление размера в меньшую сторону, но пока для простоты - просто пропускаем.
-					log.Printf("New partition size greater then can be in msdos table. SKIP IT.")
-					continue
-				}
-				partTable.GetPartition(int(item.Partition.Number)).SetLBALen(uint32(sectorSize))
+				return
+			}
+			partTable, err := mbr.Read(diskIO)
+			diskIO.Close()
+			if err != nil {
+				log.Println("Can't read partition table: ", item.Partition.Disk.Path, err)
+				return
+			}
+			newSize := item.Size + item.FreeSpace
+			sectorSize := newSize / item.Partition.Disk.SectorSizeLogical
+			if sectorSize > MAX_UINT32 {
+				// Тут возможно окргление размера в меньшую сторону, но пока для простоты - просто пропускаем.
+				log.Printf("New partition size greater then can be in msdos table. SKIP IT.")
+				return
+			}
+			partTable.GetPartition(int(item.Partition.Number)).SetLBALen(uint32(sectorSize))
 
-				diskIO, err = os.OpenFile(item.Partition.Disk.Path, os.O_WRONLY|os.O_SYNC, 0)
-				if err != nil {
-					log.Println("Can't open disk (2): ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = partTable.Write(diskIO)
+			diskIO, err = os.OpenFile(item.Partition.Disk.Path, os.O_WRONLY|os.O_SYNC, 0)
+			if err != nil {
+				log.Println("Can't open disk (2): ", item.Partition.Disk.Path, err)
 				diskIO.Close()
+				return
+			}
+			err = partTable.Write(diskIO)
+			diskIO.Close()
+			if err != nil {
+				log.Println("WARNING!!!!!! Can't write new partition table. Disk partition table can be damaged check it.")
+				return
+			}
+			if item.Child != -1 {
+				plan[item.Child].FreeSpace += item.FreeSpace
+				item.Size += item.FreeSpace
+				item.FreeSpace = 0
+			}
+			log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
+		case "gpt":
+			diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR|os.O_SYNC, 0)
+			defer diskIO.Close() // Have to be closed manually. Defer close - for protect only.
+			if err != nil {
 				if err != nil {
-					log.Println("WARNING!!!!!! Can't write new partition table. Disk partition table can be damaged check it.")
-					continue
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += item.FreeSpace
-					item.Size += item.FreeSpace
-					item.FreeSpace = 0
-				}
-				log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
-			case "gpt":
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR|os.O_SYNC, 0)
-				defer diskIO.Close() // Have to be closed manually. Defer close - for protect only.
-				if err != nil {
-					if err != nil {
-						log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
-						diskIO.Close()
-						continue
-					}
-				}
-				_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
-				if err != nil {
-					log.Println("Can't seek gpt disk: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
-				if err != nil {
-					log.Println("Can't read gpt table: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				if uint32(len(gptTable.Partitions)) < item.Partition.Number {
-					log.Println("gpt bad partition number")
+					log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
 					diskIO.Close()
-					continue
-				}
-				gptTable.Partitions[item.Partition.Number-1].LastLBA += item.FreeSpace / item.Partition.Disk.SectorSizeLogical
-				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-					diskSizeInSectors := item.Partition.Disk.Size / item.Partition.Disk.SectorSizeLogical
-					gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
-
-					if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-						log.Println("ATTENTION!!! Error in calc of GPT partition size", item.Path)
-						diskIO.Close()
-						continue
-					}
+					return
 				}
+			}
+			_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
+			if err != nil {
+				log.Println("Can't seek gpt disk: ", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
+			if err != nil {
+				log.Println("Can't read gpt table: ", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			if uint32(len(gptTable.Partitions)) < item.Partition.Number {
+				log.Println("gpt bad partition number")
+				diskIO.Close()
+				return
+			}
+			gptTable.Partitions[item.Partition.Number-1].LastLBA += item.FreeSpace / item.Partition.Disk.SectorSizeLogical
+			if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
+				// Disk can grow in the plan (LV or loop device of guest)
+				// Диск может вырасти в плане (LV или loop-устройство гостя)
+				diskSize := item.Partition.Disk.Size
+				if size := getDiskSize(item.Partition.Disk.Path); size > diskSize {
+					diskSize = size
+				}
+				diskSizeInSectors := diskSize / item.Partition.Disk.SectorSizeLogical
+				gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
 
-				// First write table at end of disk, becouse it can be empty after extend of phisical disk.
-				// Сначала записываем таблицу разделов в конец диска, т.к. она может отсутствовать на обычном месте после расширения диска
-				err = gptTable.CreateOtherSideTable().Write(diskIO)
-				if err != nil {
-					log.Println("WARNING!!! Write GPT PRIMARY TABLE error. DATA MAY BE LOST.", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = gptTable.Write(diskIO)
-				if err != nil {
-					log.Println("WARNING!!! Write GPT SECONDARY TABLE error. DATA MAY BE LOST.", item.Path, err)
+				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
+					log.Println("ATTENTION!!! Error in calc of GPT partition size", item.Path)
 					diskIO.Close()
-					continue
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += item.FreeSpace
-					item.Size += item.FreeSpace
-					item.FreeSpace = 0
+					return
 				}
-				log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
-			default:
-				log.Printf("I don't know partition table: %v(%v)", item.Partition.Disk.PartTable, item.Path)
-				continue
 			}
-			cmd("partprobe", item.Partition.Disk.Path)
-			newKernelSize := getDiskSize(item.Path)
-			if oldKernelSize == newKernelSize && oldFreeSpace != 0 {
-				log.Println("NEED REBOOT!")
-				needReboot = true
+
+			// First write table at end of disk, becouse it can be empty after extend of phisical disk.
+			// Сначала записываем таблицу разделов в конец диска, т.к. она может отсутствовать на обычном месте после расширения диска
+			err = gptTable.CreateOtherSideTable().Write(diskIO)
+			if err != nil {
+				log.Println("WARNING!!! Write GPT PRIMARY TABLE error. DATA MAY BE LOST.", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			err = gptTable.Write(diskIO)
+			if err != nil {
+				log.Println("WARNING!!! Write GPT SECONDARY TABLE error. DATA MAY BE LOST.", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			if item.Child != -1 {
+				plan[item.Child].FreeSpace += item.FreeSpace
+				item.Size += item.FreeSpace
+				item.FreeSpace = 0
+			}
+			log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
+		default:
+			log.Printf("I don't know partition table: %v(%v)", item.Partition.Disk.PartTable, item.Path)
+			return
+		}
+		partitionsReread(item.Partition.Disk)
+		newKernelSize := getDiskSize(item.Path)
+		if oldKernelSize == newKernelSize && oldFreeSpace != 0 {
+			log.Println("NEED REBOOT!")
+			needReboot = true
+		}
+	case type_PARTITION_NEW:
+		switch item.Partition.Disk.PartTable {
+		case "msdos":
+			if item.Partition.Number > 4 {
+				log.Println("WARNING: Can't create partition with number > 4 in msdos partition table.")
+				return
+			}
+			diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
+			if err != nil {
+				log.Println("Can't create partition: ", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			partTable, err := mbr.Read(diskIO)
+			if err != nil {
+				log.Println("Can't read mbr partition table: ", item.Path, err)
+				diskIO.Close()
+				return
+			}
+			partition := partTable.GetPartition(int(item.Partition.Number))
+			if partition == nil {
+				log.Println("Can't get mbr partition: ", item.Path)
+				diskIO.Close()
+				return
+			}
+			if !partition.IsEmpty() {
+				log.Println("Mbr partition isn't empty: ", item.Path)
+				diskIO.Close()
+				return
+			}
+			partition.SetType(mbr.PART_LVM)
+			lbaStart := item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
+			if lbaStart >= MAX_UINT32 {
+				log.Println("Can't create msdos partition - sector number overflow", item.Path)
+				diskIO.Close()
+				return
+			}
+			partition.SetLBAStart(uint32(lbaStart))
+			bytesLen := item.Partition.LastByte - item.Partition.FirstByte + 1
+			lbaLen := (bytesLen) / item.Partition.Disk.SectorSizeLogical
+			if bytesLen%item.Partition.Disk.SectorSizeLogical != 0 {
+				lbaLen += 1
+			}
+			if uint64(partition.GetLBAStart())+lbaLen > MAX_UINT32 {
+				lbaLen = uint64(MAX_UINT32 - partition.GetLBAStart())
+			}
+			partition.SetLBALen(uint32(lbaLen))
+			if partTable.Check() != nil {
+				log.Println("Bad partition table after virtual create partition ", item.Path, partTable.Check())
+				diskIO.Close()
+				return
+			}
+			_, err = diskIO.Seek(0, 0)
+			if err != nil {
+				log.Println("Mbr, can't seek diskIO", err)
+				diskIO.Close()
+				return
 			}
-		case type_PARTITION_NEW:
-			switch item.Partition.Disk.PartTable {
-			case "msdos":
-				if item.Partition.Number > 4 {
-					log.Println("WARNING: Can't create partition with number > 4 in msdos partition table.")
-					continue
-				}
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
-				if err != nil {
-					log.Println("Can't create partition: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partTable, err := mbr.Read(diskIO)
-				if err != nil {
-					log.Println("Can't read mbr partition table: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partition := partTable.GetPartition(int(item.Partition.Number))
-				if partition == nil {
-					log.Println("Can't get mbr partition: ", item.Path)
-					diskIO.Close()
-					continue
-				}
-				if !partition.IsEmpty() {
-					log.Println("Mbr partition isn't empty: ", item.Path)
-					diskIO.Close()
-					continue
-				}
-				partition.SetType(mbr.PART_LVM)
-				lbaStart := item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
-				if lbaStart >= MAX_UINT32 {
-					log.Println("Can't create msdos partition - sector number overflow", item.Path)
-					diskIO.Close()
-					continue
-				}
-				partition.SetLBAStart(uint32(lbaStart))
-				bytesLen := item.Partition.LastByte - item.Partition.FirstByte + 1
-				lbaLen := (bytesLen) / item.Partition.Disk.SectorSizeLogical
-				if bytesLen%item.Partition.Disk.SectorSizeLogical != 0 {
-					lbaLen += 1
-				}
-				if uint64(partition.GetLBAStart())+lbaLen > MAX_UINT32 {
-					lbaLen = uint64(MAX_UINT32 - partition.GetLBAStart())
-				}
-				partition.SetLBALen(uint32(lbaLen))
-				if partTable.Check() != nil {
-					log.Println("Bad partition table after virtual create partition ", item.Path, partTable.Check())
-					diskIO.Close()
-					continue
-				}
-				_, err = diskIO.Seek(0, 0)
-				if err != nil {
-					log.Println("Mbr, can't seek diskIO", err)
-					diskIO.Close()
-					continue
-				}
 
-				err = partTable.Write(diskIO)
-				if err != nil {
-					log.Println("Mbr, can't write", err)
-					diskIO.Close()
-					continue
-				}
+			err = partTable.Write(diskIO)
+			if err != nil {
+				log.Println("Mbr, can't write", err)
 				diskIO.Close()
-				cmd("partprobe", item.Partition.Disk.Path)
-				log.Printf("Partition created: %v (%v)\n", item.Path, formatSize(lbaLen*item.Partition.Disk.SectorSizeLogical))
-			case "gpt":
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
-				if err != nil {
-					log.Println("Can't open disk for new partition in gpt:", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
-				if err != nil {
-					log.Println("Can't seek disk read gpt table for new partition: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
-				if err != nil {
-					log.Println("Can't read gpt table, new partition: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				if int(item.Partition.Number) >= len(gptTable.Partitions) || item.Partition.Number < 1 {
-					log.Println("Bad partition number for create partition in gpt: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				part := &gptTable.Partitions[item.Partition.Number-1]
-				part.FirstLBA = item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
-				part.LastLBA = item.Partition.LastByte / item.Partition.Disk.SectorSizeLogical
-				part.Type = gpt.GUID_LVM
+				return
+			}
+			diskIO.Close()
+			partitionsReread(item.Partition.Disk)
+			log.Printf("Partition created: %v (%v)\n", item.Path, formatSize(lbaLen*item.Partition.Disk.SectorSizeLogical))
+		case "gpt":
+			diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
+			if err != nil {
+				log.Println("Can't open disk for new partition in gpt:", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
+			if err != nil {
+				log.Println("Can't seek disk read gpt table for new partition: ", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
+			if err != nil {
+				log.Println("Can't read gpt table, new partition: ", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			if int(item.Partition.Number) >= len(gptTable.Partitions) || item.Partition.Number < 1 {
+				log.Println("Bad partition number for create partition in gpt: ", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			part := &gptTable.Partitions[item.Partition.Number-1]
+			part.FirstLBA = item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
+			part.LastLBA = item.Partition.LastByte / item.Partition.Disk.SectorSizeLogical
+			part.Type = gpt.GUID_LVM
 
-				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-					diskSizeInSectors := item.Partition.Disk.Size / item.Partition.Disk.SectorSizeLogical
-					gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
+			if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
+				// Disk can grow in the plan (LV or loop device of guest)
+				// Диск может вырасти в плане (LV или loop-устройство гостя)
+				diskSize := item.Partition.Disk.Size
+				if size := getDiskSize(item.Partition.Disk.Path); size > diskSize {
+					diskSize = size
+				}
+				diskSizeInSectors := diskSize / item.Partition.Disk.SectorSizeLogical
+				gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
 
-					if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-						log.Println("ATTENTION!!! Error in calc of GPT partition size2", item.Path)
-						diskIO.Close()
-						continue
-					}
-				}
-				err = gptTable.Write(diskIO)
-				if err != nil {
-					log.Println("WARNING ERROR WHILE WRITE PRIMARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = gptTable.CreateOtherSideTable().Write(diskIO)
-				if err != nil {
-					log.Println("WARNING ERROR WHILE WRITE SECONDARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
+				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
+					log.Println("ATTENTION!!! Error in calc of GPT partition size2", item.Path)
 					diskIO.Close()
-					continue
+					return
 				}
-				cmd("partprobe", item.Partition.Disk.Path)
-				log.Printf("New GPT partition created: %v (%v)\n", item.Path, formatSize((part.LastLBA-part.FirstLBA+1)*item.Partition.Disk.SectorSizeLogical))
-			default:
-				log.Println("Can't create partition in unknown partition table: ", item.Partition.Path, item.Partition.Disk.PartTable)
 			}
+			err = gptTable.Write(diskIO)
+			if err != nil {
+				log.Println("WARNING ERROR WHILE WRITE PRIMARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			err = gptTable.CreateOtherSideTable().Write(diskIO)
+			if err != nil {
+				log.Println("WARNING ERROR WHILE WRITE SECONDARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
+				diskIO.Close()
+				return
+			}
+			partitionsReread(item.Partition.Disk)
+			log.Printf("New GPT partition created: %v (%v)\n", item.Path, formatSize((part.LastLBA-part.FirstLBA+1)*item.Partition.Disk.SectorSizeLogical))
+		default:
+			log.Println("Can't create partition in unknown partition table: ", item.Partition.Path, item.Partition.Disk.PartTable)
+		}
 
-		case type_LVM_GROUP:
-			if item.Child != -1 {
-				plan[item.Child].FreeSpace = item.FreeSpace
-			}
-			log.Printf("Free space on LVM_GROUP '%v' %v\n", item.Path, formatSize(item.FreeSpace))
-		case type_LVM_LV:
-		retryLoop2:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Try extend LVM LV once more:", item.Path)
-					time.Sleep(time.Second)
+	case type_LVM_GROUP:
+		if item.Child != -1 {
+			plan[item.Child].FreeSpace = item.FreeSpace
+		}
+		log.Printf("Free space on LVM_GROUP '%v' %v\n", item.Path, formatSize(item.FreeSpace))
+	case type_LVM_LV:
+		limitFreeSpace(item)
+		extendArgs := []string{"-l", "+100%FREE"}
+		var extendPVs []string
+		switch {
+		case item.LVMPool != "":
+			// Virtual size of thin LV set explicitly
+			// Виртуальный размер тонкого LV задается явно
+			newSize := (item.Size + item.FreeSpace) / item.LVMExtentSize * item.LVMExtentSize
+			if newSize <= item.Size {
+				log.Printf("Thin LV %v doesn't need extend. Size: %v, pool: %v\n", item.Path, formatSize(item.Size), item.LVMPool)
+				return
+			}
+			extendArgs = []string{"-L", formatUInt(newSize) + "B"}
+		case lvmLVLayoutLimited(*item):
+			vg := item.Path[:strings.Index(item.Path, "/")]
+			extents := lvmLVUsableExtents(*item, lvmPVFreeExtents(lvmVGPVs(vg), item.LVMExtentSize))
+			if extents == 0 {
+				log.Printf("LV %v (%v) can't use free space of VG %v: there is no free space on enough separate PVs.\n",
+					item.Path, item.LVMSegType, vg)
+				return
+			}
+			if item.PolicyLimit != 0 && extents > item.FreeSpace/item.LVMExtentSize {
+				extents = item.FreeSpace / item.LVMExtentSize
+			}
+			extendArgs = []string{"-l", "+" + formatUInt(extents)}
+			if lvmIsCache(item.LVMSegType) {
+				// Allocate origin extents on PVs, which not used by cache
+				// Выделяем экстенты исходного LV на PV, не занятых кешем
+				for _, pv := range lvmLVOriginPVs(*item, lvmVGPVs(vg)) {
+					extendPVs = append(extendPVs, pv.Path)
 				}
-				cmd("lvresize", "-l", "+100%FREE", item.Path)
-				newSize := lvmLVGetSize(item.Path)
+			}
+		case item.PolicyLimit != 0:
+			// Size limited by policy - don't take all free space of volume group
+			// Размер ограничен правилами - не занимаем все свободное место группы томов
+			extents := item.FreeSpace / item.LVMExtentSize
+			if extents == 0 {
+				log.Printf("LV %v doesn't need extend. Size: %v, max size by policy: %v\n", item.Path,
+					formatSize(item.Size), formatSize(item.PolicyLimit))
+				return
+			}
+			extendArgs = []string{"-l", "+" + formatUInt(extents)}
+		}
+		cacheSplitted := false
+	retryLoop2:
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			if retry > 0 {
+				log.Println("Try extend LVM LV once more:", item.Path)
+				time.Sleep(time.Second)
+			}
+			lvmCmd("lvresize", append(append(extendArgs, item.Path), extendPVs...)...)
+			newSize := lvmLVGetSize(item.Path)
+			addSpace := newSize - item.Size
+			if item.FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+				// Some versions of LVM can't resize LV with attached cache. Split cache and try again.
+				// Некоторые версии LVM не могут изменить размер LV с подключенным кешем. Отключаем кеш и пробуем снова.
+				if lvmIsCache(item.LVMSegType) && !cacheSplitted {
+					cacheSplitted = lvmCacheSplit(*item)
+				}
+				continue retryLoop2
+			}
+			log.Printf("Resize LVM_LV %v to %v(+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+			item.Size = newSize
+			item.FreeSpace = 0
+			if item.Child != -1 {
+				plan[item.Child].FreeSpace += addSpace
+			}
+			break retryLoop2
+		}
+		if cacheSplitted {
+			lvmCacheAttach(*item)
+		}
+	case type_LOOP:
+		limitFreeSpace(item)
+		if item.FreeSpace == 0 {
+			log.Printf("Loop device %v doesn't need extend.\n", item.Path)
+			return
+		}
+		oldSize := item.Size
+		newSize, err := loopExtend(*item)
+		if err != nil {
+			log.Println("Can't extend loop device:", item.Path, err)
+			return
+		}
+		if newSize <= oldSize {
+			log.Println("Size of loop device doesn't changed:", item.Path, formatSize(newSize))
+			return
+		}
+		log.Printf("Resize loop device %v (%v) to %v(+%v)\n", item.Path, item.LoopFile, formatSize(newSize),
+			formatSize(newSize-oldSize))
+		item.Size = newSize
+		item.FreeSpace = 0
+		if item.Child != -1 {
+			plan[item.Child].FreeSpace += newSize - oldSize
+		}
+	case type_DM:
+		limitFreeSpace(item)
+		if item.FreeSpace == 0 {
+			log.Printf("Device mapper device %v doesn't need extend.\n", item.Path)
+			return
+		}
+		oldSize := item.Size
+		newSize, err := dmExtend(*item)
+		if err != nil {
+			log.Println("Can't extend device mapper device:", item.Path, err)
+			return
+		}
+		if newSize <= oldSize {
+			log.Println("Size of device mapper device doesn't changed:", item.Path, formatSize(newSize))
+			return
+		}
+		log.Printf("Resize device mapper device %v (%v) to %v(+%v)\n", item.Path, item.DMName, formatSize(newSize),
+			formatSize(newSize-oldSize))
+		item.Size = newSize
+		item.FreeSpace = 0
+		if item.Child != -1 {
+			plan[item.Child].FreeSpace += newSize - oldSize
+		}
+	case type_LVM_THIN_POOL:
+		limitFreeSpace(item)
+		data, meta := lvmThinPoolGrowth(*item, item.FreeSpace)
+		if meta > 0 {
+			res, stderr, err := lvmCmd("lvextend", "--poolmetadatasize", "+"+formatUInt(meta)+"B", item.Path)
+			if err != nil {
+				log.Printf("Can't extend metadata of thin pool %v: %v\nstdout: %v\nstderr: %v\n", item.Path, err, res, stderr)
+			} else {
+				item.LVMMetaSize += meta
+				log.Printf("Extend metadata of thin pool %v (+%v)\n", item.Path, formatSize(meta))
+			}
+		}
+		if data == 0 {
+			log.Printf("Thin pool %v doesn't need extend.\n", item.Path)
+			return
+		}
+	retryLoop5:
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			if retry > 0 {
+				log.Println("Try extend thin pool once more:", item.Path)
+				time.Sleep(time.Second)
+			}
+			lvmCmd("lvextend", "-L", "+"+formatUInt(data)+"B", item.Path)
+			newSize := lvmLVGetSize(item.Path)
+			if newSize <= item.Size {
+				continue retryLoop5
+			}
+			addSpace := newSize - item.Size
+			log.Printf("Resize thin pool %v to %v(+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+			item.Size = newSize
+			item.FreeSpace = 0
+			if item.Child != -1 {
+				plan[item.Child].FreeSpace += lvmThinLVGrowth(plan[item.Child], addSpace)
+			}
+			break retryLoop5
+		}
+	case type_LVM_PV:
+	retryLoop:
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			if retry > 0 {
+				log.Println("Try to resize LVM PV once more:", item.Path)
+			}
+			lvmCmd("pvresize", item.Path)
+			newSize := lvmPVGetSize(item.Path)
+			addSpace := newSize - item.Size
+			if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+				continue retryLoop
+			}
+			if item.Child != -1 {
+				plan[item.Child].FreeSpace += addSpace
+			}
+			log.Printf("LVM PV Resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+			useFreeSpace(item, addSpace)
+			item.Size = newSize
+			break retryLoop
+		}
+	case type_LVM_PV_ADD:
+		vg := plan[item.Child].Path
+		oldSize, _, _ := lvmVGGetSize(vg)
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			lvmCmd("vgextend", vg, item.Path)
+			newSize, _, _ := lvmVGGetSize(vg)
+			if newSize > oldSize {
+				log.Printf("Add free pv (%v) to vg(%v), new size: %v(+%v)\n", item.Path, vg,
+					formatSize(newSize), formatSize(newSize-oldSize))
+				break
+			}
+		}
+	case type_LVM_PV_NEW:
+		vg := plan[item.Child].Path
+		oldSize, _, _ := lvmVGGetSize(vg)
+	retryLoop3:
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			lvmCmd("pvcreate", lvmPVCreateArgs(item.Path)...)
+			lvmCmd("vgextend", vg, item.Path)
+			newSize, _, _ := lvmVGGetSize(vg) // Yes - create LVM PV, but check size of LVM VG. It is OK.
+			addSpace := newSize - oldSize
+			if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+				log.Println("Try extend VG once more: ", vg, item.Path)
+				time.Sleep(time.Second)
+				continue retryLoop3
+			} else {
+				log.Printf("Add PV %v (+%v)\n", item.Path, formatSize(newSize-oldSize))
+				break retryLoop3
+			}
+		}
+
+	case type_FS:
+		if item.FSEnable64bit {
+			fsEnable64bitExt(item)
+		}
+		limitFreeSpace(item)
+		if item.FreeSpace == 0 && item.OverLimit > 0 {
+			log.Printf("Filesystem %v has max size (%v). Can't extend it.\n", item.Path, formatSize(item.MaxSize))
+			return
+		}
+		if fsOffline(item.FSType) {
+			newSize, err := fsOfflineResize(*item)
+			if newSize > item.Size {
 				addSpace := newSize - item.Size
-				if item.FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					continue retryLoop2
-				}
-				log.Printf("Resize LVM_LV %v to %v(+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+				useFreeSpace(item, addSpace)
 				item.Size = newSize
-				item.FreeSpace = 0
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += addSpace
-				}
-				break retryLoop2
+				log.Printf("Resize filesystem (offline): %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
 			}
-		case type_LVM_PV:
-		retryLoop:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Try to resize LVM PV once more:", item.Path)
-				}
-				cmd("pvresize", item.Path)
-				newSize := lvmPVGetSize(item.Path)
+			if err != nil {
+				log.Printf("Can't resize filesystem %v: %v\n", item.Path, err)
+			}
+			return
+		}
+		if options.ResizeBackend != backend_TOOLS && item.FreeSpace >= item.FSBlockSize && item.FSBlockSize > 0 {
+			newSize, err := fsResizeNative(*item)
+			if err == nil && newSize <= item.Size {
+				err = fmt.Errorf("Filesystem doesn't extend")
+			}
+			switch {
+			case err == nil:
 				addSpace := newSize - item.Size
-				if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					continue retryLoop
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += addSpace
-				}
-				log.Printf("LVM PV Resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
-				item.FreeSpace -= addSpace
+				useFreeSpace(item, addSpace)
 				item.Size = newSize
-				break retryLoop
-			}
-		case type_LVM_PV_ADD:
-			vg := plan[item.Child].Path
-			oldSize, _, _ := lvmVGGetSize(vg)
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				cmd("vgextend", vg, item.Path)
-				newSize, _, _ := lvmVGGetSize(vg)
-				if newSize > oldSize {
-					log.Printf("Add free pv (%v) to vg(%v), new size: %v(+%v)\n", item.Path, vg,
-						formatSize(newSize), formatSize(newSize-oldSize))
-					break
-				}
+				log.Printf("Resize filesystem (native): %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
+				return
+			case options.ResizeBackend == backend_NATIVE:
+				log.Printf("Can't resize filesystem %v: %v\n", item.Path, err)
+				return
+			default:
+				log.Printf("Can't resize filesystem %v natively, use tools: %v\n", item.Path, err)
 			}
-		case type_LVM_PV_NEW:
-			vg := plan[item.Child].Path
-			oldSize, _, _ := lvmVGGetSize(vg)
-		retryLoop3:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				cmd("pvcreate", item.Path)
-				cmd("vgextend", vg, item.Path)
-				newSize, _, _ := lvmVGGetSize(vg) // Yes - create LVM PV, but check size of LVM VG. It is OK.
-				addSpace := newSize - oldSize
-				if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					log.Println("Try extend VG once more: ", vg, item.Path)
-					time.Sleep(time.Second)
-					continue retryLoop3
-				} else {
-					log.Printf("Add PV %v (+%v)\n", item.Path, formatSize(newSize-oldSize))
-					break retryLoop3
-				}
+		}
+	retryLoop4:
+		for retry := 0; retry < TRY_COUNT; retry++ {
+			if retry > 0 {
+				log.Println("Sleep a second before retry.")
+				time.Sleep(time.Second)
 			}
-
-		case type_FS:
-		retryLoop4:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Sleep a second before retry.")
-					time.Sleep(time.Second)
+			switch item.FSType {
+			case "ext3", "ext4":
+				resizeArgs := []string{"-f", item.Path}
+				if item.OverLimit > 0 {
+					// Filesystem can't use all space of device - set size explicitly in filesystem blocks.
+					// Файловая система не может занять все устройство - явно указываем размер в блоках файловой системы.
+					resizeArgs = append(resizeArgs, formatUInt((item.Size+item.FreeSpace)/item.FSBlockSize))
+				}
+				res, stderr, _ := cmd("resize2fs", resizeArgs...)
+				newSize, err := fsGetSizeExt(item.Path)
+				if err != nil {
+					log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
+					continue retryLoop4
+				}
+				addSpace := newSize - item.Size
+				if addSpace == 0 {
+					log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
+					continue retryLoop4
 				}
-				switch item.FSType {
-				case "ext3", "ext4":
-					res, stderr, _ := cmd("resize2fs", "-f", item.Path)
-					newSize, err := fsGetSizeExt(item.Path)
+				useFreeSpace(item, addSpace)
+				item.Size = newSize
+				log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
+				break retryLoop4
+			case "xfs":
+				var tmpMountPoint string
+				var mountPoint string
+				if mountPoint, _ = getMountPoint(item.Path); mountPoint == "" {
+					var err error
+					tmpMountPoint, err = ioutil.TempDir("", "")
 					if err != nil {
-						log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
+						log.Println("Can't create tmp mount point for xfs.")
 						continue retryLoop4
 					}
-					addSpace := newSize - item.Size
-					if addSpace == 0 {
-						log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
-						continue retryLoop4
-					}
-					item.FreeSpace -= addSpace
-					item.Size = newSize
-					log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
-					break retryLoop4
-				case "xfs":
-					var tmpMountPoint string
-					var mountPoint string
-					if mountPoint, _ = getMountPoint(item.Path); mountPoint == "" {
-						var err error
-						tmpMountPoint, err = ioutil.TempDir("", "")
-						if err != nil {
-							log.Println("Can't create tmp mount point for xfs.")
-							continue retryLoop4
+					var errString string
+				xfsMountLoop:
+					for retryXFSMount := 0; retryXFSMount < TRY_COUNT; retryXFSMount++ {
+						if retryXFSMount > 0 {
+							log.Println("Retry mount xfs volume")
+							time.Sleep(time.Second)
+						}
+						if _, errString, err = cmd("mount", "-t", "xfs", item.Path, tmpMountPoint); err == nil {
+							mountPoint = tmpMountPoint
+							break xfsMountLoop
 						}
-						var errString string
-					xfsMountLoop:
-						for retryXFSMount := 0; retryXFSMount < TRY_COUNT; retryXFSMount++ {
-							if retryXFSMount > 0 {
-								log.Println("Retry mount xfs volume")
-								time.Sleep(time.Second)
-							}
-							if _, errString, err = cmd("mount", "-t", "xfs", item.Path, tmpMountPoint); err == nil {
-								mountPoint = tmpMountPoint
-								break xfsMountLoop
-							}
-							log.Printf("Can't xfs mount: %v (%v) ('%v' -> '%v')", err, errString, item.Path, tmpMountPoint)
-							if retryXFSMount == TRY_COUNT-1 {
-								// При последней попытке - удаляем временную точку монтирования
-								os.Remove(tmpMountPoint)
-								break retryLoop4
-							}
+						log.Printf("Can't xfs mount: %v (%v) ('%v' -> '%v')", err, errString, item.Path, tmpMountPoint)
+						if retryXFSMount == TRY_COUNT-1 {
+							// При последней попытке - удаляем временную точку монтирования
+							os.Remove(tmpMountPoint)
+							break retryLoop4
 						}
 					}
+				}
 
-					res, stderr, _ := cmd("xfs_growfs", mountPoint)
-					newSize, err := fsGetSizeXFS(item.Path)
+				growArgs := []string{mountPoint}
+				if item.OverLimit > 0 && item.FSBlockSize > 0 {
+					// Filesystem can't use all space of device - set size explicitly in filesystem blocks.
+					// Файловая система не может занять все устройство - явно указываем размер в блоках файловой системы.
+					growArgs = []string{"-D", formatUInt((item.Size + item.FreeSpace) / item.FSBlockSize), mountPoint}
+				}
+				res, stderr, _ := cmd("xfs_growfs", growArgs...)
+				newSize, _, err := fsGetInfoXFS(item.Path)
 
-					if tmpMountPoint != "" {
-						cmd("umount", tmpMountPoint)
-						os.Remove(tmpMountPoint)
-					}
+				if tmpMountPoint != "" {
+					cmd("umount", tmpMountPoint)
+					os.Remove(tmpMountPoint)
+				}
 
-					if err != nil {
-						log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
-						continue retryLoop4
-					}
-					addSpace := newSize - item.Size
-					item.FreeSpace -= addSpace
-					item.Size = newSize
-					if addSpace == 0 {
-						log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
-						continue retryLoop4
-					}
-					log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
-					break retryLoop4
-				default:
-					log.Println("I don't know the filesystem: ", item.Path, item.FSType)
+				if err != nil {
+					log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
+					continue retryLoop4
 				}
+				addSpace := newSize - item.Size
+				useFreeSpace(item, addSpace)
+				item.Size = newSize
+				if addSpace == 0 {
+					log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
+					continue retryLoop4
+				}
+				log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
+				break retryLoop4
+			default:
+				log.Println("I don't know the filesystem: ", item.Path, item.FSType)
 			}
-		case type_SKIP:
-			log.Println("Skip item:", item.SkipReason, item.OldType, item.Path, formatSize(item.Size))
-		case type_UNKNOWN:
-			log.Println("Unknown item type:", item.Type, item.Path)
-		default:
-			log.Println("I don't know way to resize type: ", item.Type)
 		}
+	case type_DISK:
+		// Disk grows outside (hypervisor, storage system), layer above already see its size
+		// Диск растет снаружи (гипервизор, система хранения), слой над ним уже видит его размер
+		log.Printf("Disk %v has size %v. It doesn't need extend.\n", item.Path, formatSize(item.Size))
+	case type_SWAP:
+		limitFreeSpace(item)
+		if item.FreeSpace > 0 {
+			if err := swapFormat(item.Path); err != nil {
+				log.Println("ATTENTION!!! Can't make swap on extended device:", item.Path, err)
+				return
+			}
+			oldSize := item.Size
+			if res, err := probe.ProbeFile(item.Path); err == nil {
+				item.Size = res.Size
+			}
+			item.FreeSpace = 0
+			log.Printf("Resize swap %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(item.Size-oldSize))
+		}
+		if item.SwapActive {
+			if err := swapOn(item.Path, item.SwapPriority); err != nil {
+				log.Println("ATTENTION!!! Can't turn on swap. Turn on it manually:", item.Path, err)
+				return
+			}
+			log.Println("Swap turned on:", item.Path)
+		}
+	case type_SKIP:
+		log.Println("Skip item:", item.SkipReason, item.OldType, item.Path, formatSize(item.Size))
+	case type_UNKNOWN:
+		log.Println("Unknown item type:", item.Type, item.Path)
+	default:
+		log.Println("I don't know way to resize type: ", item.Type)
 	}
 	return needReboot
 }
+
+/*
+Enable 64bit feature of unmounted ext4 filesystem. If it fail - MaxSize of item stay limited by 32 bit block numbers.
+
+Включает опцию 64bit на отмонтированной файловой системе ext4. Если не получилось - MaxSize остается ограниченным
+32-битными номерами блоков.
+*/
+func fsEnable64bitExt(item *storageItem) {
+	// resize2fs require checked filesystem before convert. e2fsck exit code 1 - errors corrected, it is success too.
+	// resize2fs требует проверенную файловую систему перед конвертацией. Код 1 e2fsck - ошибки исправлены, это тоже
+	// успех.
+	res, stderr, err := cmd("e2fsck", "-f", "-p", item.Path)
+	if code := cmdExitCode(err); code != 0 && code != 1 {
+		log.Printf("Can't check filesystem before enable 64bit: %v (%v)\nstdout: %v\nstderr: %v\n", item.Path, err, res, stderr)
+	} else {
+		res, stderr, err = cmd("resize2fs", "-b", item.Path)
+		if err != nil {
+			log.Printf("Can't enable 64bit feature: %v (%v)\nstdout: %v\nstderr: %v\n", item.Path, err, res, stderr)
+		}
+	}
+
+	blockSize, features, err := fsGetFeaturesExt(item.Path)
+	if err == nil {
+		item.FSBlockSize, item.FSFeatures = blockSize, features
+	} else {
+		log.Printf("Can't read filesystem features after enable 64bit: %v (%v)\n", item.Path, err)
+	}
+	item.MaxSize = fsMaxSize(item.FSType, item.FSBlockSize, item.FSFeatures)
+	log.Printf("Max size of filesystem %v: %v\n", item.Path, formatSize(item.MaxSize))
+}
+
+// Split cache from LV. Cache pool or cache volume stay in volume group as separate LV.
+// Отключает кеш от LV. Пул или том кеша остается в группе томов отдельным LV.
+func lvmCacheSplit(item storageItem) bool {
+	log.Printf("Split cache %v from LV %v\n", item.LVMCachePool, item.Path)
+	res, stderr, err := lvmCmd("lvconvert", "-y", "--splitcache", item.Path)
+	if err != nil {
+		log.Printf("Can't split cache from LV %v: %v\nstdout: %v\nstderr: %v\n", item.Path, err, res, stderr)
+		return false
+	}
+	return true
+}
+
+/*
+Attach cache to LV after split. LVM rename attached cache volume to name_cvol and cache pool to name_cpool and can
+rename it back after split.
+
+Подключает кеш к LV после отключения. LVM переименовывает подключенный том кеша в name_cvol и пул кеша в name_cpool
+и может переименовать их обратно после отключения.
+*/
+func lvmCacheAttach(item storageItem) {
+	cachePool := item.LVMCachePool
+	for _, suffix := range []string{"_cvol", "_cpool"} {
+		if strings.HasSuffix(cachePool, suffix) && lvmLVGetSize(cachePool) == 0 {
+			cachePool = strings.TrimSuffix(cachePool, suffix)
+		}
+	}
+
+	var args []string
+	switch {
+	case item.LVMSegType == "writecache":
+		args = []string{"-y", "--type", "writecache", "--cachevol", cachePool}
+	case strings.HasSuffix(item.LVMCachePool, "_cvol"):
+		args = []string{"-y", "--type", "cache", "--cachevol", cachePool}
+	default:
+		args = []string{"-y", "--type", "cache", "--cachepool", cachePool}
+	}
+	if item.LVMCacheMode != "" {
+		args = append(args, "--cachemode", item.LVMCacheMode)
+	}
+	args = append(args, item.Path)
+
+	res, stderr, err := lvmCmd("lvconvert", args...)
+	if err != nil {
+		log.Printf("ATTENTION!!! Can't attach cache %v to LV %v. Attach it manually: lvconvert %v\n%v\nstdout: %v\nstderr: %v\n",
+			cachePool, item.Path, strings.Join(args, " "), err, res, stderr)
+		return
+	}
+	log.Printf("Attach cache %v to LV %v\n", cachePool, item.Path)
+}
+
+/*
+Resources, which step of plan touch: disk of partition, volume group of LVM items. Steps with same resources can't be
+executed concurrently. Device mapper device gets free space from every source device (stripes), so parents of it lock
+the device: they change its FreeSpace.
+
+Ресурсы, которые затрагивает шаг плана: диск раздела, группа томов элементов LVM. Шаги с одинаковыми ресурсами не могут
+выполняться одновременно. Устройство device mapper получает свободное место от каждого исходного устройства (полосы),
+поэтому его родители блокируют устройство: они меняют его FreeSpace.
+*/
+func extendDoResources(plan []storageItem, i int) (res []string) {
+	item := plan[i]
+	switch item.Type {
+	case type_PARTITION, type_PARTITION_NEW:
+		res = append(res, "disk:"+item.Partition.Disk.Path)
+	case type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
+		// partprobe of other partition on the disk can temporary remove device of PV
+		// partprobe другого раздела на этом диске может временно удалить устройство PV
+		if diskPath, ok := deviceDisk(item.Path); ok {
+			res = append(res, "disk:"+diskPath)
+		}
+		if item.Child != -1 {
+			res = append(res, "vg:"+plan[item.Child].Path)
+		}
+	case type_LVM_GROUP:
+		res = append(res, "vg:"+item.Path)
+	case type_LOOP:
+		res = append(res, "file:"+item.LoopFile)
+	case type_DM:
+		res = append(res, "dm:"+item.DMName)
+	case type_LVM_LV, type_LVM_THIN_POOL:
+		if slash := strings.Index(item.Path, "/"); slash != -1 {
+			res = append(res, "vg:"+item.Path[:slash])
+		}
+	}
+	if item.Child != -1 && plan[item.Child].Type == type_DM {
+		res = append(res, "dm:"+plan[item.Child].DMName)
+	}
+	return res
+}
+
+// Named locks. Many locks taken in sorted order for avoid deadlocks.
+// Именованные блокировки. Несколько блокировок берутся в отсортированном порядке, чтобы избежать взаимоблокировок.
+type resourceLocks struct {
+	mutex sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newResourceLocks() *resourceLocks {
+	return &resourceLocks{locks: make(map[string]*sync.Mutex)}
+}
+
+func (this *resourceLocks) get(name string) *sync.Mutex {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	lock, ok := this.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		this.locks[name] = lock
+	}
+	return lock
+}
+
+func (this *resourceLocks) Lock(names []string) {
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		this.get(name).Lock()
+	}
+}
+
+func (this *resourceLocks) Unlock(names []string) {
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		this.get(name).Unlock()
+	}
+}
@@ -13,6 +13,21 @@ import (
 const FILTER_LVM_ALREADY_PLACED = "LVM_ALREADY_PLACED"
 const REGEXP_CHARS = "^*+?[]"
 
+// Options for make extend plan.
+// Параметры построения плана расширения.
+type planOptions struct {
+	Filter          string // Filter of disks, which use for partition extends. Фильтр дисков для расширения разделов
+	Ext4Enable64bit bool   // Enable 64bit feature of unmounted ext4 if it need for extend. Включать опцию 64bit отмонтированной ext4, если это нужно для расширения
+	ThinOvercommit  uint64 // How many percents thin LV can exceed its pool. На сколько процентов тонкий LV может превышать свой пул
+
+	TargetSize        uint64                   // Max size of filesystem. 0 - unlimited. Максимальный размер файловой системы. 0 - без ограничений
+	VGReserve         uint64                   // Free space, which stay in volume group. Свободное место, которое остается в группе томов
+	Layers            map[storageItemType]bool // Layers, which can be extended. nil - all. Слои, которые можно расширять. nil - все
+	DenyNewPartitions bool                     // Deny create new partitions. Запретить создание новых разделов
+	LoopAllocate      bool                     // Allocate space for growth of loop file instead of sparse growth. Выделять место при росте файла loop-устройства вместо разреженного роста
+	AllowUmount       bool                     // Allow umount of filesystems, which grow offline only. Разрешить отмонтирование файловых систем, которые растут только offline
+}
+
 func expandFilter(storage []storageItem, filter string) string {
 	var expressions = make(map[string]bool)
 	for _, part := range strings.Split(filter, ",") {
@@ -27,9 +42,9 @@ func expandFilter(storage []storageItem, filter string) string {
 					if part.Child != vgIndex || part.Type != type_LVM_PV {
 						continue
 					}
-					diskPath, _, err := extractPartNumber(part.Path)
-					if err != nil {
-						log.Println("Can't extract disk path.", part.Type, part.Path, err)
+					diskPath, ok := deviceDisk(part.Path)
+					if !ok {
+						log.Println("Can't extract disk path.", part.Type, part.Path)
 						continue
 					}
 					express := "^" + diskPath + "[^/]*$"
@@ -62,8 +77,8 @@ storage can be modify while work the function. You have to store copy of them if
 storage - описание иерархии и возможных путей расширения раздела. storage[0] - вершина, целевая точка расширения.
 в процессе работы функции storage может портиться. Если важно его сохранение нужно сохранить у себя копию.
 */
-func extendPlan(storage []storageItem, filter string) (plan []storageItem, err error) {
-	filter = expandFilter(storage, filter)
+func extendPlan(storage []storageItem, options planOptions) (plan []storageItem, err error) {
+	filter := expandFilter(storage, options.Filter)
 	filterRE, err := regexp.Compile(filter)
 	if err != nil {
 		err = errors.New("Error while compile filter regexp: " + err.Error())
@@ -86,6 +101,48 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 		}
 	}
 
+	/*
+		Cancel extend of layers and create of partitions, which disabled by policy
+		Отменяем расширение слоев и создание разделов, запрещенные правилами
+	*/
+	for i := range storage {
+		item := &storage[i]
+		switch {
+		case item.Type == type_SKIP || item.Type == type_UNKNOWN:
+		case options.Layers != nil && !options.Layers[item.Type]:
+			skipStorageItem(item, "Layer disabled by policy.")
+		case options.DenyNewPartitions && (item.Type == type_PARTITION_NEW || item.Type == type_LVM_PV_NEW):
+			skipStorageItem(item, "Create of new partitions disabled by policy.")
+		}
+	}
+
+	for i := range storage {
+		if storage[i].Type == type_LOOP {
+			storage[i].LoopAllocate = options.LoopAllocate
+		}
+	}
+
+	/*
+		Filesystems, which grow offline only, have to be unmounted for resize
+		Файловые системы, которые растут только offline, для расширения нужно отмонтировать
+	*/
+	for i := range storage {
+		item := &storage[i]
+		if item.Type != type_FS || !fsOffline(item.FSType) {
+			continue
+		}
+		mount, mounted, err := fsOfflineMount(item.Path)
+		switch {
+		case err != nil:
+			skipStorageItem(item, fmt.Sprintf("Can't umount filesystem for resize: %v.", err))
+		case mounted && !options.AllowUmount:
+			skipStorageItem(item, fmt.Sprintf("Filesystem %v grows offline only and mounted to %v. Umount disabled by policy.",
+				item.FSType, mount.MountPoint))
+		default:
+			item.FSUmount = mounted
+		}
+	}
+
 	/*
 		When it can create new partition or extend current partition - always select extend.
 		Если есть возможность расширить существующий раздел и создать новый на этом же месте - выбираем расширение
@@ -166,6 +223,14 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 		}
 	}
 
+	// Striped and raid LV can use only part of free space of volume group
+	// LV с чередованием и raid могут использовать только часть свободного места группы томов
+	lvmPlanLayout(storage)
+
+	// Thin LV extends by its pool
+	// Тонкий LV расширяется за счет пула
+	lvmPlanThin(storage, options.ThinOvercommit)
+
 	// map storage index and plan index. planIndex = planMap[storageIndex]
 	// соответствие индексов storage индексам plan. planIndex = planMap[storageIndex]
 	planMap := make(map[int]int)
@@ -188,9 +253,305 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 		item := &plan[i]
 		item.Child = planMap[item.Child]
 	}
+
+	/*
+		Enable 64bit feature of ext4 if filesystem can't use all free space without it. resize2fs -b work with unmounted
+		filesystem only.
+		Включаем опцию 64bit у ext4, если без нее файловая система не может занять все свободное место. resize2fs -b
+		работает только с отмонтированной файловой системой.
+	*/
+	for i, item := range planPropagateFreeSpace(plan) {
+		if item.Type != type_FS || item.FSType != "ext4" || item.OverLimit == 0 {
+			continue
+		}
+		if options.TargetSize != 0 && options.TargetSize <= item.MaxSize {
+			continue
+		}
+		if !options.Ext4Enable64bit {
+			log.Printf("Filesystem %v can't be extended over %v without 64bit feature. It can be enabled for unmounted filesystem by --enable-64bit.\n",
+				item.Path, formatSize(item.MaxSize))
+			continue
+		}
+		if mountPoint, err := getMountPoint(item.Path); err == nil {
+			log.Printf("Filesystem %v can't be extended over %v without 64bit feature. Umount it (%v) for enable 64bit.\n",
+				item.Path, formatSize(item.MaxSize), mountPoint)
+			continue
+		}
+		plan[i].FSEnable64bit = true
+		plan[i].MaxSize = ext_MAX_SIZE_64BIT
+		limitFreeSpace(&plan[i])
+	}
+
+	planApplyLimits(plan, options)
 	return plan, nil
 }
 
+/*
+Limit extend by policy: target size of filesystem and free space, which stay in volume groups. Device under filesystem
+limited by target size too, for avoid take space, which filesystem doesn't use.
+
+Ограничивает расширение по правилам: целевой размер файловой системы и свободное место, остающееся в группах томов.
+Устройство под файловой системой тоже ограничивается целевым размером, чтобы не занимать место, которое файловая
+система не использует.
+*/
+func planApplyLimits(plan []storageItem, options planOptions) {
+	if options.TargetSize != 0 {
+		for fsIndex, fs := range plan {
+			if fs.Type != type_FS && fs.Type != type_SWAP {
+				continue
+			}
+			planSetLimit(&plan[fsIndex], options.TargetSize)
+
+			var growth uint64
+			if options.TargetSize > fs.Size {
+				growth = options.TargetSize - fs.Size
+			}
+			for i := range plan {
+				item := &plan[i]
+				if item.Child == fsIndex && (item.Type == type_LVM_LV || item.Type == type_PARTITION || item.Type == type_LOOP ||
+					item.Type == type_DM) {
+					planSetLimit(item, item.Size+growth)
+				}
+			}
+		}
+	}
+
+	if options.VGReserve != 0 {
+		for _, vg := range planPropagateFreeSpace(plan) {
+			if vg.Type != type_LVM_GROUP || vg.Child == -1 {
+				continue
+			}
+			var usable uint64
+			if vg.FreeSpace > options.VGReserve {
+				usable = vg.FreeSpace - options.VGReserve
+			}
+			child := &plan[vg.Child]
+			planSetLimit(child, child.Size+usable)
+		}
+	}
+}
+
+// Set limit of item size by policy. Limit can only decrease.
+// Устанавливает ограничение размера по правилам. Ограничение может только уменьшаться.
+func planSetLimit(item *storageItem, limit uint64) {
+	if item.PolicyLimit == 0 || limit < item.PolicyLimit {
+		item.PolicyLimit = limit
+	}
+	if item.MaxSize == 0 || limit < item.MaxSize {
+		item.MaxSize = limit
+	}
+	limitFreeSpace(item)
+}
+
+/*
+Skip items, which already planned for other target. It used for plan many targets: storage, which shared between
+targets (disk, volume group) is planned once. planned - map of item key to target, it updated by the function.
+
+Отменяет элементы, которые уже запланированы для другой цели. Используется при планировании нескольких целей:
+общие устройства (диск, группа томов) планируются один раз. planned - соответствие ключа элемента цели, обновляется
+функцией.
+*/
+func planSkipShared(plan []storageItem, planned map[string]string, target string) {
+	for i := range plan {
+		item := &plan[i]
+		if item.Type == type_SKIP || item.Type == type_UNKNOWN {
+			continue
+		}
+		keys := storageItemKeys(*item)
+		shared := ""
+		for _, key := range keys {
+			if other, ok := planned[key]; ok && other != target {
+				shared = other
+				break
+			}
+		}
+		if shared != "" {
+			skipStorageItem(item, "Already planned for "+shared)
+			// PV can't be created on skipped partition
+			// PV не может быть создан на отмененном разделе
+			if item.OldType == type_PARTITION_NEW && item.Child != -1 && plan[item.Child].Type == type_LVM_PV_NEW {
+				skipStorageItem(&plan[item.Child], "Already planned for "+shared)
+			}
+			continue
+		}
+		for _, key := range keys {
+			planned[key] = target
+		}
+	}
+}
+
+/*
+Keys of storage item for detect shared storage. Partition has own key and key of free space after it, if it grows into
+the space. New partition has key of free space, which it takes.
+
+Ключи элемента для определения общих устройств. У раздела свой ключ и ключ свободного места после него, если он растет
+в это место. У нового раздела ключ свободного места, которое он занимает.
+*/
+func storageItemKeys(item storageItem) []string {
+	switch item.Type {
+	case type_PARTITION:
+		keys := []string{item.Path}
+		if item.FreeSpace > 0 {
+			keys = append(keys, diskGapKey(item.Partition.Disk.Path, item.Partition.LastByte+1))
+		}
+		return keys
+	case type_PARTITION_NEW:
+		return []string{diskGapKey(item.Partition.Disk.Path, item.Partition.FirstByte)}
+	case type_LVM_GROUP:
+		return []string{"vg:" + item.Path}
+	default:
+		return []string{item.Path}
+	}
+}
+
+// Key of free space on disk by its first byte.
+// Ключ свободного места на диске по его первому байту.
+func diskGapKey(diskPath string, firstByte uint64) string {
+	return "disk:" + diskPath + ":" + formatUInt(firstByte)
+}
+
+// Selector of plan steps: step indexes and layers.
+// Выбор шагов плана: номера шагов и слои.
+type stepSelector struct {
+	Indexes map[int]bool
+	Layers  map[storageItemType]bool
+}
+
+// Parse comma separated list of step indexes and layer names, for example "0,3,partition,pv".
+// Разбирает список номеров шагов и имен слоев через запятую, например "0,3,partition,pv".
+func parseStepSelector(s string) (sel stepSelector, err error) {
+	sel.Indexes = make(map[int]bool)
+	var layers []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if index, err := strconv.Atoi(part); err == nil && index >= 0 {
+			sel.Indexes[index] = true
+		} else {
+			layers = append(layers, part)
+		}
+	}
+	sel.Layers, err = parseLayers(strings.Join(layers, ","))
+	return sel, err
+}
+
+func (sel stepSelector) match(index int, item storageItem) bool {
+	return sel.Indexes[index] || sel.Layers[item.Type]
+}
+
+/*
+Skip steps of plan, which doesn't selected for execution. only - execute only the steps, skip - don't execute the steps,
+until - execute steps up to first matched step (include it). Empty string - no selection.
+Skipped step doesn't provide its growth to next steps, but free space, which already exist in next steps, stay usable.
+Volume group step only pass free space, so it selected together with its LV.
+
+Отменяет шаги плана, которые не выбраны для выполнения. only - выполнить только эти шаги, skip - не выполнять эти
+шаги, until - выполнить шаги до первого подходящего шага (включительно). Пустая строка - без выбора.
+Отмененный шаг не передает свой прирост следующим шагам, но свободное место, уже имеющееся в следующих шагах, остается
+доступным. Шаг группы томов только передает свободное место, поэтому выбирается вместе со своим LV.
+*/
+func planSelectSteps(plan []storageItem, only, skip, until string) error {
+	selected := make([]bool, len(plan))
+	for i := range selected {
+		selected[i] = true
+	}
+	var selectors [3]stepSelector
+	for i, s := range []string{only, skip, until} {
+		if s == "" {
+			continue
+		}
+		var err error
+		selectors[i], err = parseStepSelector(s)
+		if err != nil {
+			return err
+		}
+		for index := range selectors[i].Indexes {
+			if index >= len(plan) {
+				return fmt.Errorf("Plan hasn't step %v", index)
+			}
+		}
+	}
+	onlySel, skipSel, untilSel := selectors[0], selectors[1], selectors[2]
+
+	untilIndex := len(plan) - 1
+	if until != "" {
+		untilIndex = -1
+		for i, item := range plan {
+			if untilSel.match(i, item) {
+				untilIndex = i
+				break
+			}
+		}
+		if untilIndex == -1 {
+			return fmt.Errorf("Plan hasn't step for --until: %v", until)
+		}
+	}
+
+	for i, item := range plan {
+		if only != "" && !onlySel.match(i, item) || skip != "" && skipSel.match(i, item) || i > untilIndex {
+			selected[i] = false
+		}
+	}
+	// Child of item is always after the item in plan
+	// Потомок элемента в плане всегда находится после него
+	for i := len(plan) - 1; i >= 0; i-- {
+		if plan[i].Type == type_LVM_GROUP && plan[i].Child != -1 {
+			selected[i] = selected[plan[i].Child]
+		}
+	}
+
+	for i := range plan {
+		item := &plan[i]
+		if !selected[i] && item.Type != type_SKIP && item.Type != type_UNKNOWN {
+			skipStorageItem(item, "Step doesn't selected for execution.")
+		}
+	}
+	return nil
+}
+
+/*
+Return copy of plan, where FreeSpace of every item include space, which will be provided by underliing items while
+execute the plan. FreeSpace is limited by MaxSize of item.
+
+Возвращает копию плана, в которой FreeSpace каждого элемента включает место, которое будет получено от нижележащих
+устройств при выполнении плана. FreeSpace ограничивается MaxSize элемента.
+*/
+func planPropagateFreeSpace(plan []storageItem) []storageItem {
+	res := make([]storageItem, len(plan))
+	copy(res, plan)
+	for i := range res {
+		item := &res[i]
+		if item.Type == type_DM && len(item.DMDevices) > 1 {
+			var parents []storageItem
+			for _, parent := range res[:i] {
+				if parent.Child == i && parent.Type != type_SKIP {
+					parents = append(parents, parent)
+				}
+			}
+			item.FreeSpace = dmStripedFreeSpace(*item, parents)
+		}
+		limitFreeSpace(item)
+		if item.Type == type_SKIP || item.Child == -1 {
+			continue
+		}
+		res[item.Child].FreeSpace += childGrowth(*item, res[item.Child], item.FreeSpace)
+	}
+	return res
+}
+
+// Return growth of child, when item provide freeSpace to it.
+// Возвращает рост child, когда item предоставляет ему freeSpace.
+func childGrowth(item, child storageItem, freeSpace uint64) uint64 {
+	if item.Type == type_LVM_THIN_POOL {
+		freeSpace, _ = lvmThinPoolGrowth(item, freeSpace)
+	}
+	if child.Type == type_DM && len(child.DMDevices) > 1 {
+		// Striped device counts growth of all stripes itself
+		// Устройство с чередованием само учитывает рост всех полос
+		return 0
+	}
+	return lvmThinLVGrowth(child, freeSpace)
+}
+
 func formatUInt(num uint64) string {
 	return strconv.FormatUint(num, 10)
 }
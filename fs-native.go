@@ -0,0 +1,156 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Backends of filesystem resize.
+// Способы изменения размера файловой системы.
+const (
+	backend_AUTO   = "auto"   // Native, if it fail - tools. Встроенный, при ошибке - утилиты
+	backend_NATIVE = "native" // Kernel ioctls only. Только ioctl ядра
+	backend_TOOLS  = "tools"  // resize2fs, xfs_growfs
+)
+
+// Linux ioctl request number, as _IOC macro.
+// Номер запроса ioctl linux, как макрос _IOC.
+func ioc(dir, typ, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | typ<<8 | nr
+}
+
+const (
+	ioc_WRITE = 1
+	ioc_READ  = 2
+)
+
+// struct xfs_fsop_geom_v1 from xfs_fs.h
+type xfsGeometry struct {
+	BlockSize    uint32
+	RtExtSize    uint32
+	AgBlocks     uint32
+	AgCount      uint32
+	LogBlocks    uint32
+	SectSize     uint32
+	InodeSize    uint32
+	Imaxpct      uint32
+	DataBlocks   uint64
+	RtBlocks     uint64
+	RtExtents    uint64
+	LogStart     uint64
+	UUID         [16]byte
+	Sunit        uint32
+	Swidth       uint32
+	Version      int32
+	Flags        uint32
+	LogSectSize  uint32
+	RtSectSize   uint32
+	DirBlockSize uint32
+}
+
+// struct xfs_growfs_data from xfs_fs.h
+type xfsGrowfsData struct {
+	NewBlocks uint64
+	Imaxpct   uint32
+	_         uint32
+}
+
+var (
+	ext4_IOC_RESIZE_FS       = ioc(ioc_WRITE, 'f', 16, 8)
+	xfs_IOC_FSGEOMETRY_V1    = ioc(ioc_READ, 'X', 100, unsafe.Sizeof(xfsGeometry{}))
+	xfs_IOC_FSGROWFSDATA     = ioc(ioc_WRITE, 'X', 110, unsafe.Sizeof(xfsGrowfsData{}))
+	errNativeResizeNoMounted = errors.New("Native resize of ext filesystem works for mounted filesystem only")
+)
+
+func ioctl(f *os.File, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+/*
+Resize filesystem by kernel ioctl up to Size+FreeSpace of item. Return new size: xfs geometry is read from kernel
+(XFS_IOC_FSGEOMETRY_V1), ext3/4 size is read from superblock on device - kernel hasn't ioctl for it, statfs doesn't count
+overhead of filesystem. Superblock is updated by resize ioctl in page cache of device, so it is actual.
+ext3/4 resized online only. Unmounted xfs mounted to temporary directory.
+
+Изменяет размер файловой системы через ioctl ядра до Size+FreeSpace элемента. Возвращает новый размер: геометрия xfs
+читается из ядра (XFS_IOC_FSGEOMETRY_V1), размер ext3/4 - из суперблока на устройстве: у ядра нет ioctl для него, statfs
+не учитывает служебное место файловой системы. ioctl изменения размера обновляет суперблок в кеше страниц устройства,
+так что он актуален. ext3/4 расширяется только смонтированной. Отмонтированная xfs монтируется во временную папку.
+*/
+func fsResizeNative(item storageItem) (newSize uint64, err error) {
+	if item.FSBlockSize == 0 {
+		return 0, fmt.Errorf("Unknown block size of filesystem: %v", item.Path)
+	}
+	blocks := (item.Size + item.FreeSpace) / item.FSBlockSize
+
+	switch item.FSType {
+	case "ext3", "ext4":
+		mountPoint, err := getMountPoint(item.Path)
+		if err != nil {
+			return 0, errNativeResizeNoMounted
+		}
+		f, err := os.Open(mountPoint)
+		if err != nil {
+			return 0, err
+		}
+		err = ioctl(f, ext4_IOC_RESIZE_FS, unsafe.Pointer(&blocks))
+		f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("EXT4_IOC_RESIZE_FS: %v", err)
+		}
+		newSize, err = fsGetSizeExt(item.Path)
+		return newSize, err
+	case "xfs":
+		mountPoint, err := getMountPoint(item.Path)
+		if err != nil {
+			mountPoint, err = ioutil.TempDir("", "")
+			if err != nil {
+				return 0, err
+			}
+			defer os.Remove(mountPoint)
+			if err = syscall.Mount(item.Path, mountPoint, "xfs", 0, ""); err != nil {
+				return 0, fmt.Errorf("Can't mount xfs: %v", err)
+			}
+			defer syscall.Unmount(mountPoint, 0)
+		}
+		f, err := os.Open(mountPoint)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+
+		geometry, err := fsGeometryXFS(f)
+		if err != nil {
+			return 0, err
+		}
+		if blocks > geometry.DataBlocks {
+			grow := xfsGrowfsData{NewBlocks: blocks, Imaxpct: geometry.Imaxpct}
+			if err = ioctl(f, xfs_IOC_FSGROWFSDATA, unsafe.Pointer(&grow)); err != nil {
+				return 0, fmt.Errorf("XFS_IOC_FSGROWFSDATA: %v", err)
+			}
+			if geometry, err = fsGeometryXFS(f); err != nil {
+				return 0, err
+			}
+		}
+		return geometry.DataBlocks * uint64(geometry.BlockSize), nil
+	default:
+		return 0, fmt.Errorf("Native resize doesn't support filesystem: %v", item.FSType)
+	}
+}
+
+// f - any file or directory of mounted xfs.
+// f - любой файл или папка смонтированной xfs.
+func fsGeometryXFS(f *os.File) (geometry xfsGeometry, err error) {
+	if err = ioctl(f, xfs_IOC_FSGEOMETRY_V1, unsafe.Pointer(&geometry)); err != nil {
+		return geometry, fmt.Errorf("XFS_IOC_FSGEOMETRY: %v", err)
+	}
+	return geometry, nil
+}
@@ -0,0 +1,162 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rekby/fsextender/probe"
+	"log"
+	"strings"
+)
+
+var errOfflineBusy = errors.New("Filesystem is busy, can't umount it")
+
+// Filesystems, which can grow only unmounted.
+// Файловые системы, которые могут расти только в отмонтированном состоянии.
+func fsOffline(fsType string) bool {
+	switch fsType {
+	case probe.TYPE_F2FS, probe.TYPE_VFAT, probe.TYPE_NTFS:
+		return true
+	default:
+		return false
+	}
+}
+
+// Mounts of device by major:minor.
+// Точки монтирования устройства по major:minor.
+func deviceMounts(mounts []mountInfo, major, minor int) (res []mountInfo) {
+	for _, mount := range mounts {
+		if mount.Major == major && mount.Minor == minor {
+			res = append(res, mount)
+		}
+	}
+	return res
+}
+
+/*
+Mounts of filesystem on device. Error if filesystem mounted more then once - it can't be remounted with same options
+reliably.
+
+Точки монтирования файловой системы устройства. Ошибка, если она смонтирована больше одного раза - ее нельзя надежно
+смонтировать обратно с теми же параметрами.
+*/
+func fsOfflineMount(path string) (mount mountInfo, mounted bool, err error) {
+	major, minor := getMajorMinor(path)
+	if major == 0 {
+		return mount, false, nil
+	}
+	mounts, err := readMountInfo()
+	if err != nil {
+		return mount, false, err
+	}
+	found := deviceMounts(mounts, major, minor)
+	switch len(found) {
+	case 0:
+		return mount, false, nil
+	case 1:
+		return found[0], true, nil
+	default:
+		return mount, true, fmt.Errorf("Filesystem %v mounted %v times", path, len(found))
+	}
+}
+
+/*
+Command for resize unmounted filesystem to newSize bytes. sectorSize - logical sector size of device: resize.f2fs counts
+size in the sectors.
+
+Команда изменения размера отмонтированной файловой системы до newSize байт. sectorSize - логический размер сектора
+устройства: resize.f2fs считает размер в этих секторах.
+*/
+func fsOfflineResizeCmd(fsType, path string, newSize, sectorSize uint64) (name string, args []string) {
+	switch fsType {
+	case probe.TYPE_F2FS:
+		return "resize.f2fs", []string{"-t", formatUInt(newSize / sectorSize), path}
+	case probe.TYPE_VFAT:
+		return "fatresize", []string{"-s", formatUInt(newSize), path}
+	case probe.TYPE_NTFS:
+		// Twice --force skips confirmation
+		// Двойной --force отключает запрос подтверждения
+		return "ntfsresize", []string{"--force", "--force", "-s", formatUInt(newSize), path}
+	default:
+		return "", nil
+	}
+}
+
+/*
+Arguments of mount, which mount filesystem back with same options. Super options are passed to kernel filesystems,
+FUSE (ntfs-3g) gets mount options only.
+
+Аргументы mount, которые монтируют файловую систему обратно с теми же параметрами. Опции суперблока передаются
+файловым системам ядра, FUSE (ntfs-3g) получает только опции точки монтирования.
+*/
+func remountArgs(mount mountInfo) []string {
+	fsType := mount.FSType
+	options := strings.Split(mount.Options, ",")
+	if fsType == "fuseblk" {
+		fsType = "ntfs-3g"
+	} else {
+		for _, option := range strings.Split(mount.SuperOptions, ",") {
+			switch option {
+			case "", "rw", "ro", "seclabel":
+			default:
+				options = append(options, option)
+			}
+		}
+	}
+	return []string{"-t", fsType, "-o", strings.Join(options, ","), mount.Source, mount.MountPoint}
+}
+
+/*
+Resize filesystem, which can grow only offline. Umount it (if it mounted and policy allows), resize and mount back.
+Return new size of filesystem.
+
+Изменяет размер файловой системы, которая растет только offline. Отмонтирует ее (если она смонтирована и правила
+разрешают), изменяет размер и монтирует обратно. Возвращает новый размер файловой системы.
+*/
+func fsOfflineResize(item storageItem) (newSize uint64, err error) {
+	mount, mounted, err := fsOfflineMount(item.Path)
+	if err != nil {
+		return 0, err
+	}
+	if mounted {
+		if !item.FSUmount {
+			return 0, fmt.Errorf("Filesystem mounted to %v, umount disabled by policy", mount.MountPoint)
+		}
+		if _, stderr, err := cmd("umount", mount.MountPoint); err != nil {
+			return 0, fmt.Errorf("%v: %v (%v)", errOfflineBusy, mount.MountPoint, strings.TrimSpace(stderr))
+		}
+		log.Printf("Umount %v for resize %v\n", mount.MountPoint, item.Path)
+		defer func() {
+			if _, stderr, mountErr := cmd("mount", remountArgs(mount)...); mountErr != nil {
+				log.Printf("ATTENTION!!! Can't mount %v back to %v: %v (%v)\n", item.Path, mount.MountPoint, mountErr, stderr)
+				if err == nil {
+					err = mountErr
+				}
+				return
+			}
+			log.Printf("Mount %v back to %v\n", item.Path, mount.MountPoint)
+		}()
+	}
+
+	size := item.Size + item.FreeSpace
+	if item.FSBlockSize > 0 {
+		size = size / item.FSBlockSize * item.FSBlockSize
+	}
+	geometry, err := getBlockDevGeometry(item.Path)
+	if err != nil {
+		return 0, fmt.Errorf("Can't read sector size of %v: %v", item.Path, err)
+	}
+	name, args := fsOfflineResizeCmd(item.FSType, item.Path, size, geometry.SectorSizeLogical)
+	if name == "" {
+		return 0, fmt.Errorf("I don't know how to resize filesystem %v (%v)", item.Path, item.FSType)
+	}
+	res, stderr, cmdErr := cmd(name, args...)
+	info, err := probe.ProbeFile(item.Path)
+	if err != nil || info.Type != item.FSType {
+		return 0, fmt.Errorf("Can't read new size after fs resize: %v (%v). Log of resize:\nstdout:%v\nstderr:%v",
+			err, cmdErr, res, stderr)
+	}
+	if info.Size <= item.Size {
+		return info.Size, fmt.Errorf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v", res, stderr)
+	}
+	return info.Size, nil
+}
@@ -2,12 +2,16 @@ package fsextender
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -174,6 +178,23 @@ func TestReadLink(t *testing.T) {
 	if res != fn("0") || err != nil {
 		t.Error(res, err)
 	}
+
+	// Chain of relative links
+	os.Mkdir(fn("dir"), 0700)
+	os.Symlink("../1", fn("dir/2"))
+	os.Symlink("dir/2", fn("3"))
+	res, err = readLink(fn("3"))
+	if res != fn("0") || err != nil {
+		t.Error(res, err)
+	}
+
+	// Loop
+	os.Symlink("loop2", fn("loop1"))
+	os.Symlink("loop1", fn("loop2"))
+	res, err = readLink(fn("loop1"))
+	if err == nil {
+		t.Error(res)
+	}
 }
 
 func TestSortPartitionsByFirstByte(t *testing.T) {
@@ -222,3 +243,1523 @@ func TestSortPartitionsByFirstByte(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestFsMaxSize(t *testing.T) {
+	if res := fsMaxSize("ext4", 4096, []string{"has_journal", "extent", "flex_bg"}); res != ext_MAX_BLOCKS_32BIT*4096 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("ext4", 4096, []string{"has_journal", "extent", "64bit", "flex_bg"}); res != ext_MAX_SIZE_64BIT {
+		t.Error(res)
+	}
+	if res := fsMaxSize("ext3", 1024, []string{"has_journal"}); res != ext_MAX_BLOCKS_32BIT*1024 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("xfs", 4096, nil); res != 0 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("vfat", 65536, nil); res != fat_MAX_SECTORS*512 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("vfat", 512, nil); res != fat32_MAX_CLUSTERS*512 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("ntfs", 4096, nil); res != (1<<32-1)*4096 {
+		t.Error(res)
+	}
+	if res := fsMaxSize("f2fs", 4096, nil); res != 16*1024*GB {
+		t.Error(res)
+	}
+}
+
+func TestLimitFreeSpace(t *testing.T) {
+	item := storageItem{Size: 10 * GB, FreeSpace: 20 * GB}
+	limitFreeSpace(&item)
+	if item.FreeSpace != 20*GB || item.OverLimit != 0 {
+		t.Error(item)
+	}
+
+	item.MaxSize = 15 * GB
+	limitFreeSpace(&item)
+	if item.FreeSpace != 5*GB || item.OverLimit != 15*GB {
+		t.Error(item)
+	}
+
+	// Repeat call doesn't change result
+	limitFreeSpace(&item)
+	if item.FreeSpace != 5*GB || item.OverLimit != 15*GB {
+		t.Error(item)
+	}
+
+	item.MaxSize = 100 * GB
+	limitFreeSpace(&item)
+	if item.FreeSpace != 20*GB || item.OverLimit != 0 {
+		t.Error(item)
+	}
+
+	item = storageItem{Size: 20 * GB, FreeSpace: 5 * GB, MaxSize: 10 * GB}
+	limitFreeSpace(&item)
+	if item.FreeSpace != 0 || item.OverLimit != 5*GB {
+		t.Error(item)
+	}
+}
+
+func TestCmdExitCode(t *testing.T) {
+	_, _, err := cmd("sh", "-c", "exit 1")
+	if code := cmdExitCode(err); code != 1 {
+		t.Error(code, err)
+	}
+	if code := cmdExitCode(nil); code != 0 {
+		t.Error(code)
+	}
+	_, _, err = cmd("/nonexistent-command")
+	if code := cmdExitCode(err); code != -1 {
+		t.Error(code, err)
+	}
+}
+
+func TestUseFreeSpace(t *testing.T) {
+	item := storageItem{FreeSpace: 10 * GB}
+	useFreeSpace(&item, 4*GB)
+	if item.FreeSpace != 6*GB {
+		t.Error(item)
+	}
+	// Growth more then planned
+	useFreeSpace(&item, 7*GB)
+	if item.FreeSpace != 0 {
+		t.Error(item)
+	}
+}
+
+func TestLvmStripedUsable(t *testing.T) {
+	test := func(free []uint64, stripes, need uint64) {
+		if res := lvmStripedUsable(free, stripes); res != need {
+			t.Errorf("%v %v: %v != %v", free, stripes, res, need)
+		}
+	}
+	test(nil, 2, 0)
+	test([]uint64{100}, 2, 0)
+	test([]uint64{100, 100}, 2, 200)
+	test([]uint64{100, 10}, 2, 20)
+	test([]uint64{100, 10, 10}, 2, 40)
+	test([]uint64{30, 30, 30}, 2, 90)
+	test([]uint64{31, 30, 30}, 2, 90)
+	test([]uint64{50, 50, 50, 0}, 4, 0)
+	test([]uint64{50, 50, 50, 50}, 4, 200)
+	test([]uint64{50, 50}, 1, 100)
+}
+
+func TestLvmPlanLayoutStriped(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	storage := []storageItem{
+		{Type: type_FS, Path: "/dev/vg/lv", Child: -1, Size: 10 * GB},
+		{Type: type_LVM_LV, Path: "vg/lv", Child: 0, Size: 10 * GB, LVMStripes: 2, LVMExtentSize: extent},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 1, FreeSpace: 3 * GB, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 2, LVMPVFree: 1 * GB, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sdb1", Child: 2, LVMPVFree: 2 * GB, LVMExtentSize: extent},
+		{Type: type_PARTITION, Path: "/dev/sda1", Child: 3, FreeSpace: 5 * GB},
+	}
+	lvmPlanLayout(storage)
+	if storage[1].MaxSize != 14*GB {
+		t.Error(formatSize(storage[1].MaxSize))
+	}
+	// Stripe on sda1 can use 2GB: 1GB of free PV and 1GB of partition growth
+	if storage[5].FreeSpace != 1*GB || storage[5].OverLimit != 4*GB {
+		t.Error(formatSize(storage[5].FreeSpace), formatSize(storage[5].OverLimit))
+	}
+}
+
+func TestLvmPVAllocate(t *testing.T) {
+	// Free extents without extend are used first
+	usage := lvmPVAllocate(30, []uint64{20, 20, 20}, []uint64{0, 10, 20})
+	if usage[0] != 0 || usage[1] != 10 || usage[2] != 20 {
+		t.Error(usage)
+	}
+	usage = lvmPVAllocate(50, []uint64{20, 20, 5}, []uint64{0, 10, 20})
+	if usage[0] != 20 || usage[1] != 20 || usage[2] != 5 {
+		t.Error(usage)
+	}
+}
+
+func TestLvmPlanLayoutRaidUnbalanced(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	storage := []storageItem{
+		{Type: type_LVM_LV, Path: "vg/lv", Child: -1, Size: 10 * GB, LVMSegType: "raid1", LVMStripes: 2, LVMExtentSize: extent,
+			LVMImagePVs: [][]string{{"/dev/sda1"}, {"/dev/sdb1"}}},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 0, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 1, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sdb1", Child: 1, LVMPVFree: 1 * GB, LVMExtentSize: extent},
+		{Type: type_PARTITION, Path: "/dev/sda1", Child: 2, Size: 10 * GB, FreeSpace: 10 * GB},
+		{Type: type_PARTITION, Path: "/dev/sdb1", Child: 3, Size: 10 * GB, FreeSpace: 3 * GB},
+	}
+	lvmPlanLayout(storage)
+	if storage[0].MaxSize != 14*GB {
+		t.Error(formatSize(storage[0].MaxSize))
+	}
+	// Leg on sda can use 4GB only
+	if storage[4].FreeSpace != 4*GB || storage[4].OverLimit != 6*GB {
+		t.Error(formatSize(storage[4].FreeSpace), formatSize(storage[4].OverLimit))
+	}
+	if storage[5].FreeSpace != 3*GB || storage[5].OverLimit != 0 {
+		t.Error(formatSize(storage[5].FreeSpace), formatSize(storage[5].OverLimit))
+	}
+}
+
+func TestLvmPlanLayoutNewPV(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	geometry := lvmPVGeometry{PEStart: 1024 * 1024, MDAEnd: 0}
+	storage := []storageItem{
+		{Type: type_LVM_LV, Path: "vg/lv", Child: -1, Size: 10 * GB, LVMStripes: 2, LVMExtentSize: extent},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 0, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 1, LVMPVFree: 2 * GB, LVMExtentSize: extent},
+		{Type: type_LVM_PV_NEW, Path: "/dev/sdb2", Child: 1, LVMExtentSize: extent, LVMPVGeometry: geometry},
+		{Type: type_PARTITION_NEW, Path: "/dev/sdb2", Child: 3, FreeSpace: 10 * GB,
+			Partition: partition{FirstByte: 1 * GB, LastByte: 11*GB - 1}},
+	}
+	lvmPlanLayout(storage)
+	if storage[0].MaxSize != 14*GB {
+		t.Error(formatSize(storage[0].MaxSize))
+	}
+	// Stripe on sdb2 is 2GB, partition is created for it only
+	part := storage[4]
+	if part.Type != type_PARTITION_NEW || part.FreeSpace != 2*GB+1024*1024 ||
+		part.Partition.LastByte != part.Partition.FirstByte+part.FreeSpace-1 {
+		t.Error(part.Type, part.FreeSpace, part.Partition.LastByte)
+	}
+}
+
+func TestLvmRaidUsable(t *testing.T) {
+	diskOf := func(path string) string {
+		return strings.TrimRight(path, "0123456789")
+	}
+	images := [][]string{{"/dev/sda1"}, {"/dev/sdb1"}}
+
+	// Free space on disks of images
+	pvs := []lvmPV{{Path: "/dev/sda1", Free: 10}, {Path: "/dev/sdb1", Free: 20}}
+	if res := lvmRaidUsable(pvs, images, diskOf); res != 10 {
+		t.Error(res)
+	}
+
+	// New PV on disk of first image can't be used by second image
+	pvs = []lvmPV{{Path: "/dev/sda1", Free: 0}, {Path: "/dev/sda2", Free: 100}, {Path: "/dev/sdb1", Free: 0}}
+	if res := lvmRaidUsable(pvs, images, diskOf); res != 0 {
+		t.Error(res)
+	}
+
+	// Disk without images given to image with least space
+	pvs = []lvmPV{{Path: "/dev/sda1", Free: 0}, {Path: "/dev/sda2", Free: 100}, {Path: "/dev/sdb1", Free: 0}, {Path: "/dev/sdc1", Free: 50}}
+	if res := lvmRaidUsable(pvs, images, diskOf); res != 50 {
+		t.Error(res)
+	}
+
+	if res := lvmRaidDataImages("raid1", 2); res != 1 {
+		t.Error(res)
+	}
+	if res := lvmRaidDataImages("raid5_ls", 4); res != 3 {
+		t.Error(res)
+	}
+	if res := lvmRaidDataImages("raid6_zr", 5); res != 3 {
+		t.Error(res)
+	}
+	if res := lvmRaidDataImages("raid10", 4); res != 2 {
+		t.Error(res)
+	}
+}
+
+func TestLvmLVUsableExtentsCache(t *testing.T) {
+	lv := storageItem{Type: type_LVM_LV, LVMSegType: "cache", LVMCachePool: "vg/cache_cpool",
+		LVMCachePVs: []string{"/dev/sdb1"}}
+	pvs := []lvmPV{{Path: "/dev/sda1", Free: 10}, {Path: "/dev/sdb1", Free: 20}, {Path: "/dev/sdc1", Free: 5}}
+	if res := lvmLVUsableExtents(lv, pvs); res != 15 {
+		t.Error(res)
+	}
+	if res := lvmLVOriginPVs(lv, pvs); len(res) != 2 || res[0].Path != "/dev/sda1" || res[1].Path != "/dev/sdc1" {
+		t.Error(res)
+	}
+	if !lvmLVLayoutLimited(lv) {
+		t.Error()
+	}
+	if !lvmIsCache("writecache") || lvmIsCache("linear") {
+		t.Error()
+	}
+}
+
+func TestLvmPlanLayoutRaidSkip(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	storage := []storageItem{
+		{Type: type_LVM_LV, Path: "vg/lv", Child: -1, Size: 10 * GB, LVMSegType: "raid1", LVMStripes: 2, LVMExtentSize: extent,
+			LVMImagePVs: [][]string{{"/dev/sda1"}, {"/dev/sdb1"}}},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 0, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 1, LVMExtentSize: extent},
+		{Type: type_LVM_PV, Path: "/dev/sdb1", Child: 1, LVMExtentSize: extent},
+		{Type: type_PARTITION, Path: "/dev/sda1", Child: 2, FreeSpace: 5 * GB},
+		{Type: type_SKIP, OldType: type_PARTITION, Path: "/dev/sdb1", Child: 3, FreeSpace: 5 * GB},
+	}
+	lvmPlanLayout(storage)
+	if storage[0].Type != type_SKIP || storage[2].Type != type_SKIP || storage[4].Type != type_SKIP {
+		t.Error(storage)
+	}
+}
+
+func TestLvmThinPoolGrowth(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	pool := storageItem{Type: type_LVM_THIN_POOL, Size: 99 * GB, LVMMetaSize: 1 * GB, LVMExtentSize: extent}
+	data, meta := lvmThinPoolGrowth(pool, 100*GB)
+	if data != 99*GB || meta != 1*GB {
+		t.Error(formatSize(data), formatSize(meta))
+	}
+
+	// Metadata limited by max size
+	pool.LVMMetaSize = lvm_THIN_METADATA_MAX
+	data, meta = lvmThinPoolGrowth(pool, 100*GB)
+	if data != 100*GB || meta != 0 {
+		t.Error(formatSize(data), formatSize(meta))
+	}
+}
+
+func TestLvmPlanThin(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	storage := []storageItem{
+		{Type: type_LVM_LV, Path: "vg/thin", Child: -1, Size: 10 * GB, LVMPool: "vg/pool", LVMExtentSize: extent},
+		{Type: type_LVM_THIN_POOL, Path: "vg/pool", Child: 0, Size: 50 * GB, LVMExtentSize: extent},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 1, FreeSpace: 100 * GB, LVMExtentSize: extent},
+	}
+	lvmPlanThin(storage, 0)
+	if storage[0].FreeSpace != 40*GB {
+		t.Error(formatSize(storage[0].FreeSpace))
+	}
+
+	storage[0].FreeSpace = 0
+	lvmPlanThin(storage, 100)
+	if storage[0].FreeSpace != 90*GB {
+		t.Error(formatSize(storage[0].FreeSpace))
+	}
+
+	// Plan order: VG, pool, LV
+	plan := []storageItem{storage[2], storage[1], storage[0]}
+	plan[0].Child, plan[1].Child, plan[2].Child = 1, 2, -1
+	plan = planPropagateFreeSpace(plan)
+	if plan[2].FreeSpace != 290*GB {
+		t.Error(formatSize(plan[2].FreeSpace))
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	conf, err := parseConfig(strings.NewReader(`
+# defaults
+filter = /dev/sda
+new-partitions = no
+
+[/home]
+target-size = 100G
+vg-reserve = 1.5G
+layers = partition, pv, lvm_lv
+
+[/var/]
+filter =
+new-partitions = yes
+loop-allocate = on
+umount = yes
+layers = loop, fs
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Default.Filter != "/dev/sda" || conf.Default.NewPartitions || len(conf.Mounts) != 2 {
+		t.Fatal(conf)
+	}
+	home := conf.policy("/home/")
+	if home.MountPoint != "/home" || home.Filter != "/dev/sda" || home.TargetSize != 100<<30 ||
+		home.VGReserve != 3<<29 || home.NewPartitions || home.LoopAllocate || home.Umount {
+		t.Error(home)
+	}
+	if !home.Layers[type_PARTITION_NEW] || !home.Layers[type_LVM_PV_ADD] || !home.Layers[type_LVM_LV] ||
+		home.Layers[type_FS] || home.Layers[type_LVM_GROUP] {
+		t.Error(home.Layers)
+	}
+	varPolicy := conf.policy("/var")
+	if varPolicy.Filter != "" || !varPolicy.NewPartitions || !varPolicy.LoopAllocate || !varPolicy.Layers[type_LOOP] ||
+		!varPolicy.planOptions().LoopAllocate || !varPolicy.planOptions().AllowUmount {
+		t.Error(varPolicy)
+	}
+	if other := conf.policy("/opt"); other.MountPoint != "/opt" || other.Filter != "/dev/sda" {
+		t.Error(other)
+	}
+	if options := home.planOptions(); !options.DenyNewPartitions || options.TargetSize != 100<<30 {
+		t.Error(options)
+	}
+
+	for _, bad := range []string{"[home]", "[/home", "filter", "size = 1", "[/a]\nlayers = pv,bad", "vg-reserve = 1X"} {
+		if _, err := parseConfig(strings.NewReader(bad)); err == nil {
+			t.Error(bad)
+		}
+	}
+
+	if conf, err := readConfig("/not/existed/fsextender.conf", false); err != nil || conf.Default.Filter != FILTER_LVM_ALREADY_PLACED ||
+		!conf.Default.NewPartitions {
+		t.Error(conf, err)
+	}
+	if _, err := readConfig("/not/existed/fsextender.conf", true); err == nil {
+		t.Error()
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]uint64{"": 0, "0": 0, "1024": 1024, "1K": 1024, "10MiB": 10 << 20, "2gb": 2 << 30, "1.5T": 3 << 39}
+	for s, size := range tests {
+		if res, err := parseSize(s); err != nil || res != size {
+			t.Error(s, res, err)
+		}
+	}
+	for _, s := range []string{"G", "1X", "-1", "1KK"} {
+		if _, err := parseSize(s); err == nil {
+			t.Error(s)
+		}
+	}
+}
+
+func TestPlanApplyLimits(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	plan := []storageItem{
+		{Type: type_LVM_GROUP, Path: "vg", Size: 100 * extent, FreeSpace: 50 * extent, Child: 1},
+		{Type: type_LVM_LV, Path: "vg/lv", Size: 50 * extent, LVMExtentSize: extent, Child: 2},
+		{Type: type_FS, Path: "/dev/vg/lv", Size: 50 * extent, Child: -1},
+	}
+	planApplyLimits(plan, planOptions{TargetSize: 60 * extent, VGReserve: 45 * extent})
+	if plan[2].MaxSize != 60*extent || plan[2].PolicyLimit != 60*extent {
+		t.Error(plan[2])
+	}
+	// VG reserve is stricter then target size
+	if plan[1].MaxSize != 55*extent || plan[1].PolicyLimit != 55*extent {
+		t.Error(plan[1])
+	}
+	res := planPropagateFreeSpace(plan)
+	if res[1].FreeSpace != 5*extent || res[2].FreeSpace != 5*extent {
+		t.Error(res)
+	}
+}
+
+func TestPlanSkipShared(t *testing.T) {
+	disk := diskInfo{Path: "/dev/sda"}
+	plan1 := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sda1", FreeSpace: 1024,
+			Partition: partition{Disk: &disk, FirstByte: 1024, LastByte: 2047}, Child: 1},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 2},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 3},
+		{Type: type_LVM_LV, Path: "vg/home", Child: -1},
+	}
+	plan2 := []storageItem{
+		{Type: type_PARTITION_NEW, Path: "/dev/sda3", Partition: partition{Disk: &disk, FirstByte: 2048, LastByte: 3071},
+			Child: 1},
+		{Type: type_LVM_PV_NEW, Path: "/dev/sda3", Child: 2}, // Skipped with its partition
+		{Type: type_LVM_GROUP, Path: "vg", Child: 3},
+		{Type: type_LVM_LV, Path: "vg/var", Child: -1},
+	}
+	// Other partition of same disk grows into own free space
+	// Другой раздел того же диска растет в свое свободное место
+	plan3 := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sda2", FreeSpace: 1024,
+			Partition: partition{Disk: &disk, FirstByte: 4096, LastByte: 5119}, Child: 1},
+		{Type: type_FS, Path: "/dev/sda2", Child: -1},
+	}
+	planned := make(map[string]string)
+	planSkipShared(plan1, planned, "/home")
+	planSkipShared(plan2, planned, "/var")
+	planSkipShared(plan3, planned, "/srv")
+	for _, item := range append(plan1, plan3...) {
+		if item.Type == type_SKIP {
+			t.Error(item)
+		}
+	}
+	for i, item := range plan2 {
+		skipped := item.Type == type_SKIP
+		if skipped != (i != 3) {
+			t.Error(item)
+		}
+		if skipped && item.SkipReason != "Already planned for /home" {
+			t.Error(item)
+		}
+	}
+}
+
+func TestPlanFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "gpt", DiskID: "guid", SectorSizeLogical: 512}
+	disk.Partitions = []partition{{Disk: disk, Path: "/dev/sda1", Number: 1, FirstByte: 1024, LastByte: 2047}}
+	plan := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sda1", Partition: disk.Partitions[0], FreeSpace: 1024, Child: 1},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 2},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 3},
+		{Type: type_LVM_PV_NEW, Path: "/dev/sda2", Child: 2},
+		{Type: type_SKIP, OldType: type_LVM_PV_ADD, Path: "/dev/sdb1", SkipReason: "test", Child: 2},
+	}
+	fingerprints := []deviceFingerprint{{Type: type_DISK, Path: "/dev/sda", Size: 4096, PartTable: "gpt", DiskID: "guid",
+		Partitions: []partitionExtent{{Number: 1, FirstByte: 1024, LastByte: 2047}}}}
+	path := filepath.Join(dir, "plan.json")
+	err = writePlanFile(path, planFile{Version: planFile_VERSION, Plans: []savedPlan{{Target: "/home", Plan: plan}},
+		Fingerprints: fingerprints})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := loadPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Plans) != 1 || file.Plans[0].Target != "/home" || len(file.Plans[0].Plan) != len(plan) {
+		t.Fatal(file)
+	}
+	loaded := file.Plans[0].Plan
+	if loaded[0].Partition.Disk == nil || loaded[0].Partition.Disk.Path != "/dev/sda" || loaded[0].Partition.Number != 1 ||
+		loaded[0].FreeSpace != 1024 || loaded[4].Type != type_SKIP || loaded[4].OldType != type_LVM_PV_ADD {
+		t.Error(loaded)
+	}
+	if err = compareFingerprints(fingerprints, file.Fingerprints); err != nil {
+		t.Error(err)
+	}
+
+	changed := []deviceFingerprint{fingerprints[0]}
+	changed[0].Partitions = []partitionExtent{{Number: 1, FirstByte: 1024, LastByte: 4095}}
+	if err = compareFingerprints(fingerprints, changed); err == nil {
+		t.Error("Changed partition doesn't detected")
+	}
+
+	expected := []struct {
+		fpType storageItemType
+		path   string
+		ok     bool
+	}{{type_DISK, "/dev/sda", true}, {type_LVM_PV, "/dev/sda1", true}, {type_LVM_GROUP, "vg", true},
+		{type_UNKNOWN, "", false}, {type_UNKNOWN, "", false}}
+	for i, item := range plan {
+		fpType, path, ok := fingerprintDevice(item)
+		if fpType != expected[i].fpType || path != expected[i].path || ok != expected[i].ok {
+			t.Error(i, fpType, path, ok)
+		}
+	}
+
+	ioutil.WriteFile(path, []byte(`{"Version": 100}`), 0600)
+	if _, err = loadPlan(path); err == nil {
+		t.Error("Unsupported version loaded")
+	}
+}
+
+func TestPlanSelectSteps(t *testing.T) {
+	makePlan := func() []storageItem {
+		return []storageItem{
+			{Type: type_PARTITION, Path: "/dev/sda1", FreeSpace: 10, Child: 1},
+			{Type: type_LVM_PV, Path: "/dev/sda1", FreeSpace: 1, Child: 2},
+			{Type: type_LVM_GROUP, Path: "vg", FreeSpace: 5, Child: 3},
+			{Type: type_LVM_LV, Path: "vg/lv", Child: 4},
+			{Type: type_FS, Path: "/dev/vg/lv", FreeSpace: 2, Child: -1},
+		}
+	}
+	skipped := func(plan []storageItem) (res []int) {
+		for i, item := range plan {
+			if item.Type == type_SKIP {
+				res = append(res, i)
+			}
+		}
+		return res
+	}
+
+	tests := []struct {
+		only, skip, until string
+		skipped           []int
+	}{
+		{"", "", "", nil},
+		{"partition,pv", "", "", []int{2, 3, 4}},
+		{"", "fs", "", []int{4}},
+		{"", "", "lvm_lv", []int{4}},
+		{"", "", "1", []int{2, 3, 4}},
+		{"3,4", "", "", []int{0, 1}},
+		{"", "lv", "", []int{2, 3}},
+	}
+	for _, test := range tests {
+		plan := makePlan()
+		if err := planSelectSteps(plan, test.only, test.skip, test.until); err != nil {
+			t.Error(test, err)
+			continue
+		}
+		if res := skipped(plan); !reflect.DeepEqual(res, test.skipped) {
+			t.Error(test, res)
+		}
+	}
+
+	// Free space of VG and own free space of FS stay usable when lower steps skipped
+	plan := makePlan()
+	planSelectSteps(plan, "lv,fs", "", "")
+	res := planPropagateFreeSpace(plan)
+	if res[3].FreeSpace != 5 || res[4].FreeSpace != 7 {
+		t.Error(res)
+	}
+
+	for _, bad := range [][3]string{{"10", "", ""}, {"bad", "", ""}, {"", "", "lvm_thin_pool"}} {
+		if err := planSelectSteps(makePlan(), bad[0], bad[1], bad[2]); err == nil {
+			t.Error(bad)
+		}
+	}
+}
+
+func TestExtendDoResources(t *testing.T) {
+	disk := &diskInfo{Path: "/dev/sda"}
+	plan := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sda1", Partition: partition{Disk: disk}, Child: 1},
+		{Type: type_LVM_PV, Path: "/dev/sda1", Child: 3},
+		{Type: type_LVM_PV_NEW, Path: "/dev/mapper/pv", Child: 3},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 4},
+		{Type: type_LVM_LV, Path: "vg/lv", Child: 5},
+		{Type: type_FS, Path: "/dev/vg/lv", Child: -1},
+	}
+	expected := [][]string{{"disk:/dev/sda"}, {"disk:/dev/sda", "vg:vg"}, {"vg:vg"}, {"vg:vg"}, {"vg:vg"}, nil}
+	for i := range plan {
+		if res := extendDoResources(plan, i); !reflect.DeepEqual(res, expected[i]) {
+			t.Error(i, res)
+		}
+	}
+}
+
+func TestExtendDoResourcesDM(t *testing.T) {
+	sdb, sdc := &diskInfo{Path: "/dev/sdb"}, &diskInfo{Path: "/dev/sdc"}
+	plan := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sdb1", Partition: partition{Disk: sdb}, Child: 2},
+		{Type: type_PARTITION, Path: "/dev/sdc1", Partition: partition{Disk: sdc}, Child: 2},
+		{Type: type_DM, Path: "/dev/mapper/data", DMName: "data", Child: -1},
+	}
+	expected := [][]string{{"disk:/dev/sdb", "dm:data"}, {"disk:/dev/sdc", "dm:data"}, {"dm:data"}}
+	for i := range plan {
+		if res := extendDoResources(plan, i); !reflect.DeepEqual(res, expected[i]) {
+			t.Error(i, res)
+		}
+	}
+
+	// Parents change FreeSpace of common child concurrently. go test -race finds race without lock of child.
+	// Родители меняют FreeSpace общего потомка параллельно. go test -race находит гонку без блокировки потомка.
+	locks := newResourceLocks()
+	start := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			resources := extendDoResources(plan, i)
+			locks.Lock(resources)
+			defer locks.Unlock(resources)
+			for j := 0; j < 1000; j++ {
+				plan[plan[i].Child].FreeSpace++
+				runtime.Gosched()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	if plan[2].FreeSpace != 2000 {
+		t.Error(plan[2].FreeSpace)
+	}
+}
+
+func TestResourceLocks(t *testing.T) {
+	locks := newResourceLocks()
+	locks.Lock([]string{"vg:vg", "disk:/dev/sda", "vg:vg"})
+	locked := make(chan bool)
+	go func() {
+		locks.Lock([]string{"disk:/dev/sda"})
+		locked <- true
+		locks.Unlock([]string{"disk:/dev/sda"})
+	}()
+	// Other resource doesn't wait
+	locks.Lock([]string{"disk:/dev/sdb"})
+	locks.Unlock([]string{"disk:/dev/sdb"})
+	select {
+	case <-locked:
+		t.Error("Resource locked twice")
+	default:
+	}
+	locks.Unlock([]string{"vg:vg", "disk:/dev/sda", "vg:vg"})
+	<-locked
+}
+
+func TestExtendDoSkipped(t *testing.T) {
+	plan := []storageItem{
+		{Type: type_SKIP, Path: "/dev/sda1", Child: 3},
+		{Type: type_SKIP, Path: "/dev/sdb1", Child: 3},
+		{Type: type_SKIP, Path: "/dev/sdc1", Child: 3},
+		{Type: type_SKIP, Path: "vg", Child: 4},
+		{Type: type_SKIP, Path: "vg/lv", Child: -1},
+	}
+	for _, jobs := range []int{0, 1, 3, 10} {
+		if extendDo(plan, doOptions{Jobs: jobs}) {
+			t.Error(jobs)
+		}
+	}
+}
+
+func TestNativeIoctlNumbers(t *testing.T) {
+	if ext4_IOC_RESIZE_FS != 0x40086610 {
+		t.Errorf("%x", ext4_IOC_RESIZE_FS)
+	}
+	if xfs_IOC_FSGEOMETRY_V1 != 0x80705864 {
+		t.Errorf("%x", xfs_IOC_FSGEOMETRY_V1)
+	}
+	if xfs_IOC_FSGROWFSDATA != 0x4010586e {
+		t.Errorf("%x", xfs_IOC_FSGROWFSDATA)
+	}
+	if blk_SSZGET != 0x1268 || blk_PBSZGET != 0x127b || blk_GETSIZE64 != 0x80081272 {
+		t.Errorf("%x %x %x", blk_SSZGET, blk_PBSZGET, blk_GETSIZE64)
+	}
+}
+
+func TestFsGetSizeExt(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	sb := make([]byte, 2048)
+	binary.LittleEndian.PutUint32(sb[1024+0x04:], 1000)
+	binary.LittleEndian.PutUint32(sb[1024+0x18:], 2) // 4096
+	binary.LittleEndian.PutUint16(sb[1024+0x38:], 0xEF53)
+	binary.LittleEndian.PutUint32(sb[1024+0x150:], 1)
+	f.Write(sb)
+
+	// blocks_count_hi ignored without 64bit
+	if size, err := fsGetSizeExt(f.Name()); err != nil || size != 1000*4096 {
+		t.Error(size, err)
+	}
+	if blockSize, features, err := fsGetFeaturesExt(f.Name()); err != nil || blockSize != 4096 || len(features) != 0 {
+		t.Error(blockSize, features, err)
+	}
+
+	binary.LittleEndian.PutUint32(sb[1024+0x60:], 0x80)
+	f.WriteAt(sb, 0)
+	if size, err := fsGetSizeExt(f.Name()); err != nil || size != (1<<32+1000)*4096 {
+		t.Error(size, err)
+	}
+	if _, features, err := fsGetFeaturesExt(f.Name()); err != nil || !reflect.DeepEqual(features, []string{"64bit"}) {
+		t.Error(features, err)
+	}
+
+	f.WriteAt([]byte{0, 0}, 1024+0x38)
+	if _, err := fsGetSizeExt(f.Name()); err == nil {
+		t.Error("Bad magic doesn't detected")
+	}
+}
+
+func TestDevMajorMinor(t *testing.T) {
+	tests := []struct {
+		dev          uint64
+		major, minor int
+	}{
+		{0x0801, 8, 1},
+		{0xfd03, 253, 3},
+		{0x10300, 259, 0},    // nvme
+		{0x10fd00, 253, 256}, // minor > 255
+		{0x100000000800, 0x1008, 0},
+	}
+	for _, test := range tests {
+		if major, minor := devMajorMinor(test.dev); major != test.major || minor != test.minor {
+			t.Errorf("%x: %v:%v, expected %v:%v", test.dev, major, minor, test.major, test.minor)
+		}
+	}
+}
+
+func TestBlockDevCache(t *testing.T) {
+	if major, minor := getMajorMinor("/dev/null"); major != 1 || minor != 3 {
+		t.Error(major, minor)
+	}
+	if _, ok := blockDevCached.getMajorMinor("/dev/null"); ok {
+		t.Error("Cache used outside of scan")
+	}
+
+	blockDevCached.Begin()
+	getMajorMinor("/dev/null")
+	if mm, ok := blockDevCached.getMajorMinor("/dev/null"); !ok || mm != [2]int{1, 3} {
+		t.Error(mm, ok)
+	}
+	blockDevCached.setGeometry("/dev/test", blockDevGeometry{Size: 1024, SectorSizeLogical: 512, SectorSizePhysical: 4096})
+	if geometry, err := getBlockDevGeometry("/dev/test"); err != nil || geometry.Size != 1024 ||
+		geometry.SectorSizePhysical != 4096 {
+		t.Error(geometry, err)
+	}
+	blockDevCached.End()
+
+	if _, err := getBlockDevGeometry("/dev/test"); err == nil {
+		t.Error("Cache used after end of scan")
+	}
+}
+
+func TestLvmReport(t *testing.T) {
+	report, err := parseLvmReport([]byte(`  {
+      "report": [
+          {
+              "lv": [
+                  {"vg_name":"vg/1", "lv_name":"data|x", "lv_kernel_major":"253", "lv_kernel_minor":"2", "lv_size":"1073741824", "lv_uuid":"u1"},
+                  {"vg_name":"vg/1", "lv_name":"[data|x_rimage_0]", "lv_kernel_major":"-1", "lv_kernel_minor":"-1", "lv_size":"", "lv_uuid":"u2"}
+              ]
+          }
+      ]
+  }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvs := report.Report[0].LV
+	if len(lvs) != 2 || lvs[0].Path() != "vg/1/data|x" || lvs[0].Size != 1073741824 || lvs[1].Path() != "vg/1/data|x_rimage_0" ||
+		lvs[1].Size != 0 {
+		t.Error(lvs)
+	}
+	if major, minor, ok := lvs[0].MajorMinor(); !ok || major != 253 || minor != 2 {
+		t.Error(major, minor, ok)
+	}
+	if _, _, ok := lvs[1].MajorMinor(); ok {
+		t.Error("Inactive LV has major/minor")
+	}
+
+	if _, err = parseLvmReport([]byte(`{"report": [{"pv": [{"pv_size":"12x"}]}]}`)); err == nil {
+		t.Error("Bad number parsed")
+	}
+}
+
+func TestLvmSnapshot(t *testing.T) {
+	old := lvmState.snapshot
+	defer func() { lvmState.snapshot = old }()
+
+	lvmState.snapshot = &lvmSnapshot{
+		PVs: []lvmReportPV{
+			{Name: "/dev/sda1", VGName: "vg", Size: 10 * GB, Free: 1 * GB},
+			{Name: "/dev/sdb1", VGName: "vg", Size: 20 * GB, Free: 2 * GB},
+			{Name: "/dev/sdc1", VGName: "other", Size: 5 * GB},
+		},
+		VGs: []lvmReportVG{{Name: "vg", Size: 30 * GB, Free: 3 * GB, ExtentSize: 4 * 1024 * 1024}},
+		LVs: []lvmReportLV{{VGName: "vg", Name: "lv", Size: 27 * GB}},
+		Segs: []lvmReportSeg{
+			{VGName: "vg", LVName: "lv", SegType: "linear", Devices: "/dev/sda1(0)"},
+			{VGName: "vg", LVName: "lv", SegType: "raid1", PoolLV: "", Devices: "[lv_rimage_0](0),[lv_rimage_1](0)"},
+			{VGName: "vg", LVName: "[lv_rimage_0]", SegType: "linear", Devices: "/dev/sda1(100)"},
+			{VGName: "vg", LVName: "[lv_rimage_1]", SegType: "linear", Devices: "/dev/sdb1(100)"},
+		},
+	}
+
+	if size, free, extent := lvmVGGetSize("vg"); size != 30*GB || free != 3*GB || extent != 4*1024*1024 {
+		t.Error(size, free, extent)
+	}
+	if size := lvmLVGetSize("vg/lv"); size != 27*GB {
+		t.Error(size)
+	}
+	if size := lvmPVGetSizeTry("/dev/sdb1"); size != 20*GB {
+		t.Error(size)
+	}
+	if pvs := lvmVGPVs("vg"); len(pvs) != 2 || pvs[1].Free != 2*GB {
+		t.Error(pvs)
+	}
+	lv := lvmLVGetInfo("vg/lv")
+	if lv.SegType != "raid1" || !reflect.DeepEqual(lv.ImagePVs, [][]string{{"/dev/sda1"}, {"/dev/sdb1"}}) {
+		t.Error(lv)
+	}
+}
+
+func TestLvmPVCalcSize(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	if lvmNewPVGeometry.PEStart != 1024*1024 {
+		t.Error(lvmNewPVGeometry)
+	}
+	if args := strings.Join(lvmPVCreateArgs("/dev/sda2"), " "); args !=
+		"--dataalignment 1024k --metadatasize 1020k --metadatacopies 1 /dev/sda2" {
+		t.Error(args)
+	}
+	if size := lvmPVCalcSize(1*GB, lvmNewPVGeometry, extent); size != 1*GB-extent {
+		t.Error(size)
+	}
+	// Custom pe_start and second metadata area at end
+	geometry := lvmPVGeometry{PEStart: 8 * 1024 * 1024, MDAEnd: 1024 * 1024}
+	if size := lvmPVCalcSize(1*GB, geometry, extent); size != 1*GB-3*extent {
+		t.Error(size)
+	}
+	if size := lvmPVCalcSize(9*1024*1024, geometry, extent); size != 0 {
+		t.Error(size)
+	}
+	if size := lvmPVCalcSize(1*GB, geometry, 0); size != 0 {
+		t.Error(size)
+	}
+}
+
+func TestLvmPVReadGeometry(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// PV label with pe_start 2M and second metadata area in last 1M
+	label := make([]byte, 1024)
+	copy(label[512:], "LABELONE")
+	binary.LittleEndian.PutUint32(label[512+20:], 32)
+	copy(label[512+24:], "LVM2 001")
+	binary.LittleEndian.PutUint64(label[512+64:], 1*GB)
+	binary.LittleEndian.PutUint64(label[512+72:], 2*1024*1024)
+	binary.LittleEndian.PutUint64(label[512+104:], 4096)
+	binary.LittleEndian.PutUint64(label[512+112:], 1024*1024-4096)
+	binary.LittleEndian.PutUint64(label[512+120:], 1*GB-1024*1024)
+	binary.LittleEndian.PutUint64(label[512+128:], 1024*1024)
+	f.Write(label)
+
+	if geometry := lvmPVReadGeometry(f.Name()); geometry != (lvmPVGeometry{PEStart: 2 * 1024 * 1024, MDAEnd: 1024 * 1024}) {
+		t.Error(geometry)
+	}
+
+	// Without label - from LVM report
+	old := lvmState.snapshot
+	defer func() { lvmState.snapshot = old }()
+	lvmState.snapshot = &lvmSnapshot{PVs: []lvmReportPV{{Name: "/dev/sdx1", PEStart: 192 * 1024, MDASize: 512 * 1024,
+		MDACount: 2}}}
+	if geometry := lvmPVReadGeometry("/dev/sdx1"); geometry != (lvmPVGeometry{PEStart: 192 * 1024, MDAEnd: 512 * 1024}) {
+		t.Error(geometry)
+	}
+}
+
+func TestParseMountInfo(t *testing.T) {
+	data := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+30 22 0:40 /@home /home rw master:2 shared:3 - btrfs /dev/sdb1 rw,subvol=/@home
+31 22 8:1 /srv/data /mnt/my\040data rw - ext4 /dev/sda1 rw
+32 22 0:50 / /var/lib/docker/merged rw - overlay overlay rw,lowerdir=/l,upperdir=/var/lib/docker/upper,workdir=/w
+`
+	mounts, err := parseMountInfo(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mounts[0], mountInfo{ID: 22, ParentID: 1, Major: 8, Minor: 1, Root: "/", MountPoint: "/",
+		Options: "rw,relatime", FSType: "ext4", Source: "/dev/sda1", SuperOptions: "rw"}) {
+		t.Error(mounts[0])
+	}
+	if mounts[1].FSType != "btrfs" || mounts[1].Root != "/@home" || mounts[1].Major != 0 || mounts[1].Minor != 40 {
+		t.Error(mounts[1])
+	}
+	if mounts[2].MountPoint != "/mnt/my data" || mounts[2].Root != "/srv/data" {
+		t.Error(mounts[2])
+	}
+	if mountOption(mounts[3].SuperOptions, "upperdir") != "/var/lib/docker/upper" || mountOption("rw", "upperdir") != "" {
+		t.Error(mounts[3])
+	}
+
+	if _, err = parseMountInfo(strings.NewReader("22 1 8:1 / / rw shared:1 ext4 /dev/sda1 rw\n")); err == nil {
+		t.Error("Line without separator")
+	}
+
+	for s, res := range map[string]string{`a\040b`: "a b", `a\134b`: `a\b`, `tab\011`: "tab\t", `a\04`: `a\04`, `\x`: `\x`} {
+		if unescapeMountField(s) != res {
+			t.Error(s, unescapeMountField(s))
+		}
+	}
+}
+
+func TestFindMount(t *testing.T) {
+	mounts := []mountInfo{
+		{ID: 1, MountPoint: "/", Major: 8, Minor: 1},
+		{ID: 2, ParentID: 1, MountPoint: "/var", Major: 8, Minor: 2},
+		{ID: 3, ParentID: 1, MountPoint: "/var2", Major: 8, Minor: 3},
+		{ID: 4, ParentID: 2, MountPoint: "/var/lib/docker", Major: 8, Minor: 4},
+		{ID: 5, ParentID: 2, MountPoint: "/mnt", Major: 8, Minor: 5},
+		// Overmount: 6 mounted over 7, though listed before it
+		{ID: 6, ParentID: 7, MountPoint: "/media", Major: 8, Minor: 6},
+		{ID: 7, ParentID: 1, MountPoint: "/media", Major: 8, Minor: 7},
+	}
+	for path, id := range map[string]int{"/": 1, "/etc/fstab": 1, "/var": 2, "/var/lib": 2, "/var2/x": 3,
+		"/var/lib/docker/volumes": 4, "/var/lib/dockerx": 2, "/mnt": 5} {
+		if mount, ok := findMount(mounts, path); !ok || mount.ID != id {
+			t.Error(path, mount, ok)
+		}
+	}
+	if mount, ok := findMount(mounts, "/media/cdrom"); !ok || mount.ID != 6 {
+		t.Error(mount, ok)
+	}
+	if _, ok := findMount(mounts[1:2], "/etc"); ok {
+		t.Error("Path outside of mounts")
+	}
+}
+
+func TestMountDevice(t *testing.T) {
+	mounts := []mountInfo{
+		{ID: 1, MountPoint: "/", Minor: 30, FSType: "btrfs", Source: "/dev/sdx1", Root: "/@"},
+		{ID: 2, ParentID: 1, MountPoint: "/data", Minor: 31, FSType: "btrfs", Source: "/dev/sdx2", Root: "/@data"},
+		{ID: 3, ParentID: 1, MountPoint: "/merged", Minor: 32, FSType: "overlay", Source: "overlay",
+			SuperOptions: "rw,lowerdir=/lower,upperdir=/data/upper,workdir=/data/work"},
+		{ID: 4, ParentID: 1, MountPoint: "/tmp", Minor: 33, FSType: "tmpfs", Source: "tmpfs"},
+		{ID: 5, ParentID: 1, MountPoint: "/broken", Minor: 34, FSType: "overlay", Source: "overlay", SuperOptions: "rw"},
+	}
+	for i, res := range []string{"/dev/sdx1", "/dev/sdx2", "/dev/sdx2", "", ""} {
+		device, err := mountDevice(mounts, mounts[i])
+		if device != res || (res == "") != (err != nil) {
+			t.Error(mounts[i].MountPoint, device, err)
+		}
+	}
+}
+
+func TestParseDeviceSpec(t *testing.T) {
+	type result struct {
+		key, value string
+		ok         bool
+	}
+	for spec, res := range map[string]result{
+		"UUID=01234567-89ab":   {"UUID", "01234567-89ab", true},
+		"uuid=1234-ABCD":       {"UUID", "1234-ABCD", true},
+		`LABEL="my data"`:      {"LABEL", "my data", true},
+		"PARTUUID=1234abcd-01": {"PARTUUID", "1234abcd-01", true},
+		"PARTLABEL='root'":     {"PARTLABEL", "root", true},
+		"UUID=":                {"", "", false},
+		"/dev/sda1":            {"", "", false},
+		"/mnt/a=b":             {"", "", false},
+		"ID=123":               {"", "", false},
+	} {
+		key, value, ok := parseDeviceSpec(spec)
+		if key != res.key || value != res.value || ok != res.ok {
+			t.Error(spec, key, value, ok)
+		}
+	}
+}
+
+func TestUdevEncode(t *testing.T) {
+	for s, res := range map[string]string{"root": "root", "my data": `my\x20data`, "a/b": `a\x2fb`,
+		"1234-AB_C.d:e": "1234-AB_C.d:e", "данные": "данные"} {
+		if udevEncode(s) != res {
+			t.Error(s, udevEncode(s))
+		}
+	}
+}
+
+func TestResolveStartPoint(t *testing.T) {
+	wd, _ := os.Getwd()
+	if res, err := resolveStartPoint("dir/file"); res != filepath.Join(wd, "dir/file") || err != nil {
+		t.Error(res, err)
+	}
+	if res, err := resolveStartPoint("/home"); res != "/home" || err != nil {
+		t.Error(res, err)
+	}
+	if res, err := resolveStartPoint("UUID=no-such-uuid-fsextender"); err == nil {
+		t.Error(res)
+	}
+}
+
+func TestReadLoopInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, ok := readLoopInfoDir(dir); ok {
+		t.Error("Loop device without backing file")
+	}
+	ioutil.WriteFile(filepath.Join(dir, "backing_file"), []byte("/var/images/data.img\n"), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "offset"), []byte("1048576\n"), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "sizelimit"), []byte("0\n"), 0600)
+	if loop, ok := readLoopInfoDir(dir); !ok || loop != (loopInfo{File: "/var/images/data.img", Offset: 1024 * 1024}) {
+		t.Error(loop, ok)
+	}
+}
+
+func TestLoopFreeSpace(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// Sparse file, which already longer then device
+	f.Truncate(100 * 1024 * 1024)
+	withTail, err := loopFreeSpace(loopInfo{File: f.Name(), Offset: 4096}, 50*1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutTail, err := loopFreeSpace(loopInfo{File: f.Name(), Offset: 4096}, 100*1024*1024-4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Free space of host filesystem can change between calls
+	if diff := withTail - withoutTail; diff < 49*1024*1024 || diff > 51*1024*1024 || withTail%loop_ALIGN != 0 {
+		t.Error(withTail, withoutTail)
+	}
+
+	if _, err = loopFreeSpace(loopInfo{File: f.Name() + "-not-exist"}, 0); err == nil {
+		t.Error("Backing file doesn't exist")
+	}
+}
+
+func TestExtendPlanLoop(t *testing.T) {
+	storage := []storageItem{
+		{Type: type_FS, Path: "/dev/loop0", Size: 100 * 1024 * 1024, FreeSpace: 900 * 1024 * 1024, Child: -1},
+		{Type: type_LOOP, Path: "/dev/loop0", LoopFile: "/data.img", Size: 100 * 1024 * 1024,
+			FreeSpace: 900 * 1024 * 1024, Child: 0},
+	}
+	plan, err := extendPlan(storage, planOptions{LoopAllocate: true, TargetSize: 200 * 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].Type != type_LOOP || !plan[0].LoopAllocate || plan[0].FreeSpace != 100*1024*1024 {
+		t.Error(plan[0])
+	}
+	if res := extendDoResources(plan, 0); len(res) != 1 || res[0] != "file:/data.img" {
+		t.Error(res)
+	}
+}
+
+func TestSysDiskDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// /sys/dev/block/8:1 -> ../../devices/.../block/sda/sda1
+	disk := filepath.Join(dir, "devices", "block", "sda")
+	os.MkdirAll(filepath.Join(disk, "sda1"), 0700)
+	os.MkdirAll(filepath.Join(disk, "device"), 0700)
+	ioutil.WriteFile(filepath.Join(disk, "sda1", "partition"), []byte("1\n"), 0600)
+	os.Symlink(filepath.Join(disk, "sda1"), filepath.Join(dir, "8:1"))
+	os.Symlink(disk, filepath.Join(dir, "8:0"))
+
+	for _, dev := range []string{"8:0", "8:1"} {
+		if res := sysDiskDir(filepath.Join(dir, dev)); res != disk {
+			t.Error(dev, res)
+		}
+	}
+	if res := sysDiskDir(filepath.Join(dir, "8:2")); res != "" {
+		t.Error(res)
+	}
+
+	if sysCanRescan(disk) {
+		t.Error("Disk without rescan")
+	}
+	ioutil.WriteFile(filepath.Join(disk, "device", "rescan"), nil, 0200)
+	if !sysCanRescan(disk) {
+		t.Error("Disk with rescan")
+	}
+}
+
+func TestSysBlockType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := func(f string) string {
+		return filepath.Join(dir, f)
+	}
+	os.MkdirAll(fn("nvme0n1/device"), 0700)
+	os.MkdirAll(fn("nvme0n1/nvme0n1p1"), 0700)
+	ioutil.WriteFile(fn("nvme0n1/nvme0n1p1/partition"), []byte("1\n"), 0600)
+	os.MkdirAll(fn("dm-0/dm"), 0700)
+
+	for sysDir, res := range map[string]storageItemType{"nvme0n1": type_DISK, "nvme0n1/nvme0n1p1": type_PARTITION,
+		"dm-0": type_UNKNOWN, "not-exist": type_UNKNOWN} {
+		if sysBlockType(fn(sysDir)) != res {
+			t.Error(sysDir, sysBlockType(fn(sysDir)))
+		}
+	}
+}
+
+func TestDeviceDisk(t *testing.T) {
+	for path, res := range map[string]string{"/dev/not-exist-sda1": "/dev/not-exist-sda",
+		"/dev/not-exist-nvme0n1p2": "/dev/not-exist-nvme0n1", "/dev/mapper/not-exist": ""} {
+		if diskPath, ok := deviceDisk(path); diskPath != res || ok != (res != "") {
+			t.Error(path, diskPath, ok)
+		}
+	}
+}
+
+func TestParseSwaps(t *testing.T) {
+	swaps, err := parseSwaps(strings.NewReader(`Filename				Type		Size		Used		Priority
+/dev/dm-1                               partition	8388604		1024		-2
+/var/swap\040file                       file		1048572		0		10
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(swaps, []swapInfo{
+		{Path: "/dev/dm-1", Type: "partition", Size: 8388604 * 1024, Used: 1024 * 1024, Priority: -2},
+		{Path: "/var/swap file", Type: "file", Size: 1048572 * 1024, Priority: 10},
+	}) {
+		t.Error(swaps)
+	}
+	if _, err = parseSwaps(strings.NewReader("/dev/sda2 partition 100\n")); err == nil {
+		t.Error("Short line")
+	}
+}
+
+func TestSwapCanOff(t *testing.T) {
+	available, err := parseMemAvailable(strings.NewReader("MemTotal:       16318480 kB\nMemFree:          512000 kB\nMemAvailable:    1048576 kB\n"))
+	if err != nil || available != 1*GB {
+		t.Fatal(available, err)
+	}
+	if _, err = parseMemAvailable(strings.NewReader("MemTotal:       16318480 kB\n")); err == nil {
+		t.Error("Without MemAvailable")
+	}
+
+	if err = swapCanOff(swapInfo{Path: "/dev/sda2", Used: 512 * 1024 * 1024}, available); err != nil {
+		t.Error(err)
+	}
+	if err = swapCanOff(swapInfo{Path: "/dev/sda2", Used: available - swap_RAM_RESERVE/2}, available); err == nil {
+		t.Error("Swap used more then available RAM without reserve")
+	}
+}
+
+func TestSwapPlan(t *testing.T) {
+	const extent = 4 * 1024 * 1024
+	plan := []storageItem{
+		{Type: type_LVM_GROUP, Path: "vg", Size: 100 * extent, FreeSpace: 50 * extent, Child: 1},
+		{Type: type_LVM_LV, Path: "vg/swap", Size: 10 * extent, LVMExtentSize: extent, Child: 2},
+		{Type: type_SWAP, Path: "/dev/not-exist/swap", Size: 10 * extent, MaxSize: swap_MAX_PAGES * 4096, Child: -1,
+			SwapActive: true},
+	}
+	planApplyLimits(plan, planOptions{TargetSize: 20 * extent})
+	if plan[1].MaxSize != 20*extent || plan[2].MaxSize != 20*extent {
+		t.Error(plan)
+	}
+
+	// Swap, which isn't turned off by prepare, mustn't be turned on by its step
+	log.SetOutput(&bytes.Buffer{})
+	swapPrepare(plan)
+	log.SetOutput(os.Stderr)
+	if plan[2].SwapActive || plan[2].Type != type_SWAP {
+		t.Error(plan[2])
+	}
+}
+
+func TestDeviceMounts(t *testing.T) {
+	mounts := []mountInfo{
+		{ID: 1, MountPoint: "/", Major: 8, Minor: 1},
+		{ID: 2, MountPoint: "/boot/efi", Major: 8, Minor: 2},
+		{ID: 3, MountPoint: "/mnt/efi", Major: 8, Minor: 2},
+	}
+	if res := deviceMounts(mounts, 8, 2); len(res) != 2 || res[0].ID != 2 || res[1].ID != 3 {
+		t.Error(res)
+	}
+	if res := deviceMounts(mounts, 8, 3); len(res) != 0 {
+		t.Error(res)
+	}
+}
+
+func TestFsOfflineResizeCmd(t *testing.T) {
+	for _, test := range []struct {
+		FSType     string
+		Name       string
+		Args       []string
+		SectorSize uint64
+	}{
+		{"f2fs", "resize.f2fs", []string{"-t", "4194304", "/dev/sdb1"}, 512},
+		{"f2fs", "resize.f2fs", []string{"-t", "524288", "/dev/sdb1"}, 4096},
+		{"vfat", "fatresize", []string{"-s", "2147483648", "/dev/sdb1"}, 512},
+		{"ntfs", "ntfsresize", []string{"--force", "--force", "-s", "2147483648", "/dev/sdb1"}, 4096},
+		{"ext4", "", nil, 512},
+	} {
+		name, args := fsOfflineResizeCmd(test.FSType, "/dev/sdb1", 2*GB, test.SectorSize)
+		if name != test.Name || !reflect.DeepEqual(args, test.Args) {
+			t.Error(test.FSType, name, args)
+		}
+	}
+	if !fsOffline("vfat") || fsOffline("ext4") {
+		t.Error("fsOffline")
+	}
+}
+
+func TestRemountArgs(t *testing.T) {
+	mount := mountInfo{MountPoint: "/boot/efi", Options: "rw,relatime", FSType: "vfat", Source: "/dev/sda1",
+		SuperOptions: "rw,fmask=0077,dmask=0077,codepage=437,seclabel"}
+	if res := remountArgs(mount); !reflect.DeepEqual(res, []string{"-t", "vfat", "-o",
+		"rw,relatime,fmask=0077,dmask=0077,codepage=437", "/dev/sda1", "/boot/efi"}) {
+		t.Error(res)
+	}
+	mount = mountInfo{MountPoint: "/mnt/win", Options: "ro,nosuid", FSType: "fuseblk", Source: "/dev/sdb1",
+		SuperOptions: "rw,user_id=0,group_id=0,allow_other,blksize=4096"}
+	if res := remountArgs(mount); !reflect.DeepEqual(res, []string{"-t", "ntfs-3g", "-o", "ro,nosuid", "/dev/sdb1",
+		"/mnt/win"}) {
+		t.Error(res)
+	}
+}
+
+func TestReadDMInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, ok := readDMInfoDir(dir); ok {
+		t.Error("Device mapper device without name")
+	}
+	ioutil.WriteFile(filepath.Join(dir, "name"), []byte("data\n"), 0600)
+	if dm, ok := readDMInfoDir(dir); !ok || dm != (dmInfo{Name: "data"}) || !dmGeneric(dm) {
+		t.Error(dm, ok)
+	}
+	for _, uuid := range []string{"LVM-abcdef", "part1-mpath-3600"} {
+		if dmGeneric(dmInfo{Name: "x", UUID: uuid}) {
+			t.Error(uuid)
+		}
+	}
+}
+
+func TestParseDMTable(t *testing.T) {
+	text := "0 2097152 linear 8:17 2048\n2097152 4194304 striped 2 128 8:33 2048 8:49 4096\n"
+	table, err := parseDMTable(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(table, []dmTarget{
+		{Start: 0, Length: 2097152, Type: "linear", Devices: []dmDevice{{8, 17, 2048}}},
+		{Start: 2097152, Length: 4194304, Type: "striped", ChunkSize: 128, Devices: []dmDevice{{8, 33, 2048}, {8, 49, 4096}}},
+	}) {
+		t.Error(table)
+	}
+	if res := formatDMTable(table); res != text {
+		t.Error(res)
+	}
+	if err = dmCheckTable(table); err != nil {
+		t.Error(err)
+	}
+
+	crypt, err := parseDMTable("0 2048 crypt aes-xts-plain64 :64:logon:key 0 8:2 4096")
+	if err != nil || len(crypt) != 1 || crypt[0].Type != "crypt" || crypt[0].Devices != nil || dmCheckTable(crypt) == nil {
+		t.Error(crypt, err)
+	}
+
+	for _, bad := range []string{"0 2048", "0 x linear 8:1 0", "0 2048 linear 8:1", "0 2048 linear sda 0",
+		"0 2048 striped 2 128 8:1 0", "0 2048 striped 0 128"} {
+		if _, err = parseDMTable(bad); err == nil {
+			t.Error(bad)
+		}
+	}
+}
+
+func TestDMDeviceFree(t *testing.T) {
+	dev := dmDevice{Major: 8, Minor: 17, Offset: 2048}
+	own := []dmTarget{{Length: 1000, Type: "linear", Devices: []dmDevice{dev}}}
+
+	// Source grew: all space after segment
+	if free, toEnd := dmDeviceFree(dev, 3048, 10000*512, [][]dmTarget{own}); free != 6952*512 || !toEnd {
+		t.Error(free, toEnd)
+	}
+
+	// Other device mapper device placed after segment
+	other := []dmTarget{{Length: 1000, Type: "linear", Devices: []dmDevice{{8, 17, 5000}}}}
+	if free, toEnd := dmDeviceFree(dev, 3048, 10000*512, [][]dmTarget{own, other}); free != 1952*512 || toEnd {
+		t.Error(free, toEnd)
+	}
+
+	// Unknown holder
+	unknown := []dmTarget{{Type: "crypt"}}
+	if free, toEnd := dmDeviceFree(dev, 3048, 10000*512, [][]dmTarget{own, unknown}); free != 0 || toEnd {
+		t.Error(free, toEnd)
+	}
+
+	// Segment takes whole device
+	if free, toEnd := dmDeviceFree(dev, 3048, 3048*512, [][]dmTarget{own}); free != 0 || !toEnd {
+		t.Error(free, toEnd)
+	}
+}
+
+func TestDMGrowTable(t *testing.T) {
+	linear := []dmTarget{
+		{Start: 0, Length: 2048, Type: "linear", Devices: []dmDevice{{8, 17, 0}}},
+		{Start: 2048, Length: 2048, Type: "linear", Devices: []dmDevice{{8, 33, 0}}},
+	}
+	res, growth := dmGrowTable(linear, []uint64{10*1024*1024 + 1000}, 0)
+	if growth != 10*1024*1024 || res[1].Length != 2048+20480 || res[0].Length != 2048 || linear[1].Length != 2048 {
+		t.Error(res, growth)
+	}
+	if _, growth = dmGrowTable(linear, []uint64{10 * 1024 * 1024}, 1024*1024); growth != 1024*1024 {
+		t.Error(growth)
+	}
+
+	// Striped grows by min free space of stripes, by whole chunks (64KiB)
+	striped := []dmTarget{{Length: 4096, Type: "striped", ChunkSize: 128, Devices: []dmDevice{{8, 17, 0}, {8, 33, 0}}}}
+	res, growth = dmGrowTable(striped, []uint64{1024 * 1024, 200 * 1024}, 0)
+	if growth != 2*192*1024 || res[0].Length != 4096+2*384 {
+		t.Error(res, growth)
+	}
+
+	item := storageItem{Type: type_DM, DMTable: striped, DMDevices: []string{"/dev/sdb1", "/dev/sdc1"},
+		DMDevFree: []uint64{1024 * 1024, 0}}
+	if free := dmStripedFreeSpace(item, []storageItem{{Path: "/dev/sdc1", FreeSpace: 10 * 1024 * 1024}}); free != 2*1024*1024 {
+		t.Error(free)
+	}
+}
+
+func TestExtendPlanDM(t *testing.T) {
+	storage := []storageItem{
+		{Type: type_FS, Path: "/dev/mapper/data", FSType: "ext4", Size: 100 * 1024 * 1024, Child: -1},
+		{Type: type_DM, Path: "/dev/mapper/data", Size: 100 * 1024 * 1024, Child: 0, DMName: "data",
+			DMTable:   []dmTarget{{Length: 4096, Type: "striped", ChunkSize: 128, Devices: []dmDevice{{8, 17, 0}, {8, 33, 0}}}},
+			DMDevices: []string{"/dev/sdb1", "/dev/sdc1"}, DMDevFree: []uint64{0, 0}},
+		{Type: type_PARTITION, Path: "/dev/sdb1", Size: 50 * 1024 * 1024, FreeSpace: 30 * 1024 * 1024, Child: 1},
+		{Type: type_PARTITION, Path: "/dev/sdc1", Size: 50 * 1024 * 1024, FreeSpace: 20 * 1024 * 1024, Child: 1},
+	}
+	plan, err := extendPlan(storage, planOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range planPropagateFreeSpace(plan) {
+		if item.Type == type_FS && item.FreeSpace != 40*1024*1024 {
+			t.Error(item)
+		}
+	}
+}
+
+func TestKpartxPartNumber(t *testing.T) {
+	for uuid, number := range map[string]uint32{"part1-mpath-3600a0b80": 1, "part12-CRYPT-abc": 12, "mpath-3600": 0,
+		"LVM-part1-": 0, "part0-x": 0, "partx-y": 0} {
+		if res, ok := kpartxPartNumber(uuid); res != number || ok != (number != 0) {
+			t.Error(uuid, res, ok)
+		}
+	}
+}
+
+func TestKpartxName(t *testing.T) {
+	if res := kpartxName("mpatha", 2, nil); res != "mpatha2" {
+		t.Error(res)
+	}
+	if res := kpartxName("image0", 2, nil); res != "image0p2" {
+		t.Error(res)
+	}
+	if res := kpartxName("mpatha", 2, map[uint32]dmInfo{1: {Name: "mpatha-part1"}}); res != "mpatha-part2" {
+		t.Error(res)
+	}
+}
+
+func TestKpartxMappingName(t *testing.T) {
+	mappings := map[uint32]dmInfo{1: {Name: "vg-guest1", UUID: "part1-LVM-abc"}}
+	exists := func(name string) bool { return name == "vg-guest1" || name == "vg-guest2" }
+
+	// Holder of the disk is reloaded
+	// Держатель диска перезагружается
+	name, mapped, err := kpartxMappingName(partition{Number: 1, Path: "/dev/mapper/vg-guest1"}, "/dev/vg/guest",
+		mappings, exists)
+	if name != "vg-guest1" || !mapped || err != nil {
+		t.Error(name, mapped, err)
+	}
+
+	// LV vg/guest2 has name of partition 2
+	// LV vg/guest2 имеет имя раздела 2
+	if _, _, err = kpartxMappingName(partition{Number: 2, Path: "/dev/mapper/vg-guest2"}, "/dev/vg/guest", mappings,
+		exists); err == nil {
+		t.Error("Other device mapper device used as partition")
+	}
+
+	name, mapped, err = kpartxMappingName(partition{Number: 3, Path: "/dev/mapper/vg-guest3"}, "/dev/vg/guest",
+		mappings, exists)
+	if name != "vg-guest3" || mapped || err != nil {
+		t.Error(name, mapped, err)
+	}
+}
+
+func TestSysDevicePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(dir, "sdb")
+	os.Mkdir(disk, 0700)
+	if res := sysDevicePath(disk); res != "/dev/sdb" {
+		t.Error(res)
+	}
+	dm := filepath.Join(dir, "dm-3")
+	os.MkdirAll(filepath.Join(dm, "dm"), 0700)
+	ioutil.WriteFile(filepath.Join(dm, "dm", "name"), []byte("mpatha\n"), 0600)
+	if res := sysDevicePath(dm); res != "/dev/mapper/mpatha" {
+		t.Error(res)
+	}
+}
+
+func TestKpartxTable(t *testing.T) {
+	disk := diskInfo{Major: 253, Minor: 3}
+	part := partition{Disk: &disk, Number: 1, FirstByte: 1024 * 1024, LastByte: 101*1024*1024 - 1}
+	if res := formatDMTable(kpartxTable(part)); res != "0 204800 linear 253:3 2048\n" {
+		t.Error(res)
+	}
+	// Disk without device mapper keeps kernel names
+	disk = diskInfo{Path: "/dev/loop0"}
+	if res := part.makePath(); res != "/dev/loop0p1" {
+		t.Error(res)
+	}
+}
+
+func TestLastPartition(t *testing.T) {
+	disk := diskInfo{Partitions: []partition{
+		{Number: 2, FirstByte: 1024 * 1024, LastByte: 2*1024*1024 - 1},
+		{Number: 1, FirstByte: 2 * 1024 * 1024, LastByte: 10*1024*1024 - 1},
+		{Number: 0, FirstByte: 10 * 1024 * 1024, LastByte: 20*1024*1024 - 1},
+	}}
+	if part, ok := lastPartition(disk); !ok || part.Number != 1 {
+		t.Error(part, ok)
+	}
+	if part, ok := lastPartition(diskInfo{Partitions: disk.Partitions[2:]}); ok {
+		t.Error(part)
+	}
+}
+
+func TestGuestOpeners(t *testing.T) {
+	// Existed mappings of two partitions keep disk open, guest is shut down
+	// Существующие отображения двух разделов держат диск открытым, гость выключен
+	if res := guestOpeners(2, 2); res != 0 {
+		t.Error(res)
+	}
+	// Running guest
+	// Запущенный гость
+	if res := guestOpeners(3, 2); res != 1 {
+		t.Error(res)
+	}
+	if res := guestOpeners(0, 1); res != 0 {
+		t.Error(res)
+	}
+}
+
+func TestLvmImageConfig(t *testing.T) {
+	if res := lvmImageConfig("/dev/loop3"); res != `devices { use_devicesfile=0 filter=[ "a|^/dev/loop3(p[0-9]+)?$|", "r|.*|" ] global_filter=[ "a|^/dev/loop3(p[0-9]+)?$|", "r|.*|" ] }` {
+		t.Error(res)
+	}
+	if res := imageVGName("vg0", "/dev/loop3"); res != "vg0_loop3" {
+		t.Error(res)
+	}
+
+	lvmConfig = "devices { }"
+	defer func() { lvmConfig = "" }()
+	if res := lvmArgs([]string{"vgs"}); !reflect.DeepEqual(res, []string{"--config", "devices { }", "vgs"}) {
+		t.Error(res)
+	}
+	lvmConfig = ""
+	if res := lvmArgs([]string{"vgs"}); !reflect.DeepEqual(res, []string{"vgs"}) {
+		t.Error(res)
+	}
+}
+
+func TestImageTarget(t *testing.T) {
+	image := &imageAttachment{Loop: "/dev/loop3", Renamed: map[string]string{"vg0_loop3": "vg0"}}
+	if res, err := image.Target(0, "vg0/root"); err != nil || res != "/dev/vg0_loop3/root" {
+		t.Error(res, err)
+	}
+	if res, err := image.Target(0, "data/root"); err != nil || res != "/dev/data/root" {
+		t.Error(res, err)
+	}
+	if _, err := image.Target(0, "root"); err == nil {
+		t.Error("LV without VG")
+	}
+}
+
+func TestImageLayers(t *testing.T) {
+	layers := imageLayers(nil)
+	if layers[type_LOOP] || !layers[type_FS] || !layers[type_PARTITION] || !layers[type_LVM_LV] || layers[type_SKIP] {
+		t.Error(layers)
+	}
+	layers = imageLayers(map[storageItemType]bool{type_FS: true, type_LOOP: true})
+	if layers[type_LOOP] || !layers[type_FS] || layers[type_PARTITION] {
+		t.Error(layers)
+	}
+}
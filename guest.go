@@ -0,0 +1,138 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+var errGuestDisk = errors.New("Guest disk must be LVM logical volume or loop device")
+
+// Last partition of disk: it can grow with the disk.
+// Последний раздел диска: он может расти вместе с диском.
+func lastPartition(disk diskInfo) (res partition, ok bool) {
+	for _, part := range disk.Partitions {
+		if !part.IsFreeSpace() && (!ok || part.FirstByte > res.FirstByte) {
+			res, ok = part, true
+		}
+	}
+	return res, ok
+}
+
+// Count of opens of device mapper device. Running virtual machine keeps its disk open.
+// Количество открытий устройства device mapper. Запущенная виртуальная машина держит свой диск открытым.
+func dmOpenCount(name string) (uint64, error) {
+	res, stderr, err := cmd("dmsetup", "info", "-c", "--noheadings", "-o", "open", name)
+	if err != nil {
+		return 0, fmt.Errorf("dmsetup info %v: %v (%v)", name, err, strings.TrimSpace(stderr))
+	}
+	return parseUint(strings.TrimSpace(res))
+}
+
+/*
+Count of opens of device, which are made not by its partition mappings. Every mapping of partition (holder of disk)
+keeps disk open once, running guest opens disk itself.
+
+Количество открытий устройства, сделанных не его разделами. Каждый раздел (держатель диска) держит диск открытым один
+раз, запущенный гость открывает сам диск.
+*/
+func guestOpeners(open uint64, partitionHolders int) uint64 {
+	if open < uint64(partitionHolders) {
+		return 0
+	}
+	return open - uint64(partitionHolders)
+}
+
+/*
+Count of users of guest disk besides its partition mappings: running guest keeps disk open. Opens of loop device
+can't be read, 0 for it.
+
+Количество пользователей гостевого диска кроме его разделов: запущенный гость держит диск открытым. Открытия
+loop-устройства прочитать нельзя, для него 0.
+*/
+func guestDiskUsers(major, minor int) (uint64, error) {
+	dm, ok := readDMInfo(major, minor)
+	if !ok {
+		return 0, nil
+	}
+	open, err := dmOpenCount(dm.Name)
+	if err != nil {
+		return 0, err
+	}
+	return guestOpeners(open, len(kpartxMappings(major, minor))), nil
+}
+
+/*
+Map partitions of guest disk (disk of virtual machine on LVM LV or loop device) temporarily: device mapper mappings as
+kpartx for LV, kernel partitions (partx) for loop device. Return last partition as target of extend and function, which
+remove mappings created here (mappings created while extend too).
+
+Временно отображает разделы гостевого диска (диск виртуальной машины на LVM LV или loop-устройстве): устройства device
+mapper как kpartx для LV, разделы ядра (partx) для loop-устройства. Возвращает последний раздел как цель расширения и
+функцию, которая удаляет созданные здесь отображения (и созданные во время расширения).
+*/
+func guestMap(diskPath string) (target string, cleanup func(), err error) {
+	cleanup = func() {}
+	major, minor := getMajorMinor(diskPath)
+	if major == 0 {
+		return "", cleanup, fmt.Errorf("Can't get major/minor numbers: %v", diskPath)
+	}
+
+	dm, isDM := readDMInfo(major, minor)
+	_, isLoop := readLoopInfo(major, minor)
+	switch {
+	case isDM && strings.HasPrefix(dm.UUID, "LVM-"):
+		// Running guest keeps disk open, its partitions can't be changed from host
+		// Запущенный гость держит диск открытым, его разделы нельзя менять с хоста
+		// Mappings of partitions keep disk open too, their opens aren't counted
+		// Отображения разделов тоже держат диск открытым, их открытия не учитываются
+		if users, err := guestDiskUsers(major, minor); err != nil || users > 0 {
+			return "", cleanup, fmt.Errorf("Guest disk is used, shut down the guest: %v (%v opens, %v)", diskPath, users, err)
+		}
+		existed := kpartxMappings(major, minor)
+		cleanup = func() {
+			for number, mapping := range kpartxMappings(major, minor) {
+				if _, ok := existed[number]; ok {
+					continue
+				}
+				if _, stderr, err := cmd("dmsetup", "remove", mapping.Name); err != nil {
+					log.Printf("Can't remove mapping of guest partition: %v (%v, %v)\n", mapping.Name, err, stderr)
+				}
+			}
+		}
+		if err = kpartxUpdate(diskPath); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+	case isLoop:
+		partitions, _ := filepath.Glob(fmt.Sprintf("/sys/dev/block/%v:%v/*/partition", major, minor))
+		if len(partitions) == 0 {
+			if _, stderr, err := cmd("partx", "-a", diskPath); err != nil {
+				return "", cleanup, fmt.Errorf("partx -a %v: %v (%v)", diskPath, err, strings.TrimSpace(stderr))
+			}
+			cleanup = func() {
+				if _, stderr, err := cmd("partx", "-d", diskPath); err != nil {
+					log.Printf("Can't remove partitions of guest disk: %v (%v, %v)\n", diskPath, err, stderr)
+				}
+			}
+			cmd("udevadm", "settle")
+		}
+	default:
+		return "", cleanup, fmt.Errorf("%v: %v", errGuestDisk, diskPath)
+	}
+
+	disk, err := readDiskInfo(diskPath)
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	part, ok := lastPartition(disk)
+	if !ok {
+		cleanup()
+		return "", func() {}, fmt.Errorf("Guest disk hasn't partitions: %v", diskPath)
+	}
+	log.Printf("Guest partition: %v (%v)\n", part.Path, formatSize(part.Size()))
+	return part.Path, cleanup, nil
+}
@@ -0,0 +1,160 @@
+package fsextender
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*
+Config of LVM, which sees devices of image only: loop device and its partitions. Host VGs are hidden, devices file of
+host isn't used.
+
+Настройки LVM, в которых видны только устройства образа: loop-устройство и его разделы. Группы томов хоста скрыты,
+файл устройств хоста не используется.
+*/
+func lvmImageConfig(loopPath string) string {
+	filter := fmt.Sprintf(`[ "a|^%v(p[0-9]+)?$|", "r|.*|" ]`, regexp.QuoteMeta(loopPath))
+	return fmt.Sprintf("devices { use_devicesfile=0 filter=%v global_filter=%v }", filter, filter)
+}
+
+// Temporary name of image VG, which has same name as host VG.
+// Временное имя группы томов образа, имя которой совпадает с группой томов хоста.
+func imageVGName(name, loopPath string) string {
+	return name + "_" + filepath.Base(loopPath)
+}
+
+// Attached image: loop device and VGs of image, which were renamed and activated.
+// Подключенный образ: loop-устройство и группы томов образа, которые переименованы и активированы.
+type imageAttachment struct {
+	Loop      string
+	Renamed   map[string]string // Temporary name -> name in image. Временное имя -> имя в образе
+	Activated []string
+}
+
+/*
+Attach image file to partitioned loop device and activate its VGs. VG with same name as host VG is renamed temporarily
+for avoid collision of device mapper names. LVM commands see devices of image only until Detach.
+
+Подключает файл образа к loop-устройству с разделами и активирует его группы томов. Группа томов с таким же именем,
+как у группы томов хоста, временно переименовывается, чтобы избежать совпадения имен device mapper. До Detach команды
+LVM видят только устройства образа.
+*/
+func imageAttach(file string) (image *imageAttachment, err error) {
+	hostVGs := make(map[string]bool)
+	for _, vg := range lvmState.Reload().VGs {
+		hostVGs[vg.Name] = true
+	}
+
+	res, stderr, err := cmd("losetup", "--find", "--show", "--partscan", file)
+	if err != nil {
+		return nil, fmt.Errorf("losetup %v: %v (%v)", file, err, strings.TrimSpace(stderr))
+	}
+	image = &imageAttachment{Loop: strings.TrimSpace(res), Renamed: make(map[string]string)}
+	cmd("udevadm", "settle")
+	log.Printf("Image %v attached to %v\n", file, image.Loop)
+
+	lvmConfig = lvmImageConfig(image.Loop)
+	for _, vg := range lvmState.Reload().VGs {
+		name := vg.Name
+		if hostVGs[name] {
+			name = imageVGName(vg.Name, image.Loop)
+			// Rename is written to metadata in image. Log it before, for manual rollback if the program stops before
+			// Detach.
+			// Переименование записывается в метаданные образа. Пишем его в лог заранее, для ручного отката, если
+			// программа остановится до Detach.
+			log.Printf("VG %v (UUID %v) of image has same name as VG of host. Temporary rename it to %v. "+
+				"If the program stops before detach of image, rename it back manually: "+
+				"vgrename --config '%v' %v %v\n", vg.Name, vg.UUID, name, lvmConfig, name, vg.Name)
+			if _, stderr, err := lvmCmd("vgrename", vg.UUID, name); err != nil {
+				image.Detach()
+				return nil, fmt.Errorf("Can't rename VG of image %v: %v (%v)", vg.Name, err, strings.TrimSpace(stderr))
+			}
+			image.Renamed[name] = vg.Name
+		}
+		if _, stderr, err := lvmCmd("vgchange", "-ay", name); err != nil {
+			image.Detach()
+			return nil, fmt.Errorf("Can't activate VG of image %v: %v (%v)", name, err, strings.TrimSpace(stderr))
+		}
+		image.Activated = append(image.Activated, name)
+	}
+	return image, nil
+}
+
+// Target in image: LV (VG/LV, by name in image), partition number or last partition.
+// Цель в образе: LV (VG/LV, по имени в образе), номер раздела или последний раздел.
+func (this *imageAttachment) Target(partNumber uint32, lv string) (string, error) {
+	if lv != "" {
+		slash := strings.Index(lv, "/")
+		if slash == -1 {
+			return "", fmt.Errorf("LV must be set as VG/LV: %v", lv)
+		}
+		vg := lv[:slash]
+		for tmpName, name := range this.Renamed {
+			if name == vg {
+				vg = tmpName
+			}
+		}
+		return "/dev/" + vg + lv[slash:], nil
+	}
+
+	disk, err := readDiskInfo(this.Loop)
+	if err != nil {
+		return "", err
+	}
+	if partNumber == 0 {
+		part, ok := lastPartition(disk)
+		if !ok {
+			return "", fmt.Errorf("Image hasn't partitions")
+		}
+		return part.Path, nil
+	}
+	for _, part := range disk.Partitions {
+		if part.Number == partNumber {
+			return part.Path, nil
+		}
+	}
+	return "", fmt.Errorf("Image hasn't partition %v", partNumber)
+}
+
+/*
+Deactivate VGs of image, give them back their names and detach loop device.
+
+Деактивирует группы томов образа, возвращает им их имена и отключает loop-устройство.
+*/
+func (this *imageAttachment) Detach() {
+	for i := len(this.Activated) - 1; i >= 0; i-- {
+		if _, stderr, err := lvmCmd("vgchange", "-an", this.Activated[i]); err != nil {
+			log.Printf("Can't deactivate VG of image: %v (%v, %v)\n", this.Activated[i], err, stderr)
+		}
+	}
+	for tmpName, name := range this.Renamed {
+		if _, stderr, err := lvmCmd("vgrename", tmpName, name); err != nil {
+			log.Printf("ATTENTION!!! Can't rename VG of image back: %v -> %v (%v, %v)\n", tmpName, name, err, stderr)
+		}
+	}
+	lvmConfig = ""
+	lvmState.Invalidate()
+
+	cmd("udevadm", "settle")
+	if _, stderr, err := cmd("losetup", "-d", this.Loop); err != nil {
+		log.Printf("Can't detach image from %v: %v (%v)\n", this.Loop, err, stderr)
+		return
+	}
+	log.Printf("Image detached from %v\n", this.Loop)
+}
+
+// Layers for image: all except loop, image file doesn't grow. It have to be resized before (truncate, qemu-img).
+// Слои для образа: все, кроме loop, файл образа не растет. Его размер нужно увеличить заранее (truncate, qemu-img).
+func imageLayers(layers map[storageItemType]bool) map[storageItemType]bool {
+	res := make(map[storageItemType]bool)
+	for t := type_UNKNOWN + 1; t < type_SKIP; t++ {
+		if layers == nil || layers[t] {
+			res[t] = true
+		}
+	}
+	res[type_LOOP] = false
+	return res
+}
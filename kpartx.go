@@ -0,0 +1,212 @@
+package fsextender
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UUID of partition, which mapped by kpartx: part1-<UUID of disk>.
+// UUID раздела, созданного kpartx: part1-<UUID диска>.
+var kpartxUUIDRE = regexp.MustCompile(`^part(\d+)-`)
+
+// Number of partition by UUID of its device mapper device. ok == false if it isn't kpartx partition.
+// Номер раздела по UUID его устройства device mapper. ok == false, если это не раздел kpartx.
+func kpartxPartNumber(uuid string) (number uint32, ok bool) {
+	match := kpartxUUIDRE.FindStringSubmatch(uuid)
+	if match == nil {
+		return 0, false
+	}
+	number64, err := parseUint(match[1])
+	return uint32(number64), err == nil && number64 > 0
+}
+
+// Path of device from sysfs directory: /dev/mapper/NAME for device mapper, /dev/NAME for other devices.
+// Путь к устройству по папке sysfs: /dev/mapper/NAME для device mapper, /dev/NAME для остальных устройств.
+func sysDevicePath(sysDir string) string {
+	if dm, ok := readDMInfoDir(filepath.Join(sysDir, "dm")); ok {
+		return "/dev/mapper/" + dm.Name
+	}
+	if name := sysDeviceName(sysDir); name != "" {
+		return name
+	}
+	return "/dev/" + filepath.Base(sysDir)
+}
+
+/*
+Disk and number of kpartx partition. Disk is found by slaves of partition in sysfs, number - by UUID.
+
+Диск и номер раздела kpartx. Диск определяется по slaves раздела в sysfs, номер - по UUID.
+*/
+func kpartxPartitionDisk(major, minor int) (diskPath string, number uint32, ok bool) {
+	dm, ok := readDMInfo(major, minor)
+	if !ok {
+		return "", 0, false
+	}
+	if number, ok = kpartxPartNumber(dm.UUID); !ok {
+		return "", 0, false
+	}
+	slaves, _ := filepath.Glob(fmt.Sprintf("/sys/dev/block/%v:%v/slaves/*", major, minor))
+	if len(slaves) != 1 {
+		return "", 0, false
+	}
+	return sysDevicePath(slaves[0]), number, true
+}
+
+// kpartx partitions of disk by numbers.
+// Разделы kpartx диска по номерам.
+func kpartxMappings(diskMajor, diskMinor int) map[uint32]dmInfo {
+	res := make(map[uint32]dmInfo)
+	holders, _ := filepath.Glob(fmt.Sprintf("/sys/dev/block/%v:%v/holders/*", diskMajor, diskMinor))
+	for _, holder := range holders {
+		dm, ok := readDMInfoDir(filepath.Join(holder, "dm"))
+		if !ok {
+			continue
+		}
+		if number, ok := kpartxPartNumber(dm.UUID); ok {
+			res[number] = dm
+		}
+	}
+	return res
+}
+
+/*
+Name of device mapper device for partition, as kpartx make it: delimiter "p" if name of disk ends with digit. Existed
+partitions of disk have priority: new partition gets same delimiter as them.
+
+Имя устройства device mapper для раздела, как его делает kpartx: разделитель "p", если имя диска заканчивается цифрой.
+Приоритет у существующих разделов диска: новый раздел получает такой же разделитель.
+*/
+func kpartxName(diskName string, number uint32, mappings map[uint32]dmInfo) string {
+	for existedNumber, dm := range mappings {
+		suffix := strconv.FormatUint(uint64(existedNumber), 10)
+		if strings.HasPrefix(dm.Name, diskName) && strings.HasSuffix(dm.Name, suffix) &&
+			len(dm.Name) >= len(diskName)+len(suffix) {
+			delimiter := dm.Name[len(diskName) : len(dm.Name)-len(suffix)]
+			return diskName + delimiter + strconv.FormatUint(uint64(number), 10)
+		}
+	}
+	delimiter := ""
+	if last := diskName[len(diskName)-1]; last >= '0' && last <= '9' {
+		delimiter = "p"
+	}
+	return diskName + delimiter + strconv.FormatUint(uint64(number), 10)
+}
+
+// Path of partition on device mapper disk. ok == false if disk isn't device mapper device.
+// Путь к разделу на диске device mapper. ok == false, если диск не устройство device mapper.
+func kpartxPartitionPath(disk diskInfo, number uint32) (path string, ok bool) {
+	if disk.Major == 0 {
+		return "", false
+	}
+	dm, ok := readDMInfo(disk.Major, disk.Minor)
+	if !ok {
+		return "", false
+	}
+	mappings := kpartxMappings(disk.Major, disk.Minor)
+	if mapping, ok := mappings[number]; ok {
+		return "/dev/mapper/" + mapping.Name, true
+	}
+	return "/dev/mapper/" + kpartxName(dm.Name, number, mappings), true
+}
+
+// Table of partition mapping: linear target on disk.
+// Таблица раздела: цель linear на диске.
+func kpartxTable(part partition) []dmTarget {
+	return []dmTarget{{Start: 0, Length: part.Size() / dm_SECTOR, Type: "linear",
+		Devices: []dmDevice{{Major: part.Disk.Major, Minor: part.Disk.Minor, Offset: part.FirstByte / dm_SECTOR}}}}
+}
+
+/*
+Name of device mapper device for partition of disk. mapped - partition is mapped already: device is holder of the disk
+with partition UUID, only such device can be reloaded. Other device with same name (other LV: vg-guest1 for partition 1
+of vg/guest) must not be touched - error.
+
+Имя устройства device mapper для раздела диска. mapped - раздел уже отображен: устройство - держатель диска с UUID
+раздела, только такое устройство можно перезагружать. Другое устройство с таким же именем (другой LV: vg-guest1 для
+раздела 1 vg/guest) трогать нельзя - ошибка.
+*/
+func kpartxMappingName(part partition, diskPath string, mappings map[uint32]dmInfo,
+	exists func(name string) bool) (name string, mapped bool, err error) {
+	if mapping, ok := mappings[part.Number]; ok {
+		return mapping.Name, true, nil
+	}
+	name = strings.TrimPrefix(part.Path, "/dev/mapper/")
+	if exists(name) {
+		return "", false, fmt.Errorf("Device mapper device %v exists and isn't partition %v of %v", name, part.Number,
+			diskPath)
+	}
+	return name, false, nil
+}
+
+/*
+Update partition mappings of device mapper disk by its partition table, as kpartx -u: changed partitions reloaded with
+suspend/resume, new partitions created. Only holders of the disk with partition UUID are reloaded.
+
+Обновляет разделы диска device mapper по его таблице разделов, как kpartx -u: измененные разделы перезагружаются через
+suspend/resume, новые - создаются. Перезагружаются только держатели диска с UUID раздела.
+*/
+func kpartxUpdate(diskPath string) error {
+	disk, err := readDiskInfo(diskPath)
+	if err != nil {
+		return err
+	}
+	dm, ok := readDMInfo(disk.Major, disk.Minor)
+	if !ok {
+		return fmt.Errorf("Disk isn't device mapper device: %v", diskPath)
+	}
+	mappings := kpartxMappings(disk.Major, disk.Minor)
+	for _, part := range disk.Partitions {
+		if part.IsFreeSpace() {
+			continue
+		}
+		table := kpartxTable(part)
+		name, mapped, err := kpartxMappingName(part, diskPath, mappings, dmExists)
+		if err != nil {
+			return err
+		}
+		if mapped {
+			current, err := readDMTable(name)
+			if err == nil && reflect.DeepEqual(current, table) {
+				continue
+			}
+			if err = dmLoad(name, table); err != nil {
+				return err
+			}
+			log.Printf("Update partition mapping: /dev/mapper/%v\n", name)
+			continue
+		}
+
+		args := []string{"create", name}
+		if dm.UUID != "" {
+			args = append(args, "--uuid", fmt.Sprintf("part%v-%v", part.Number, dm.UUID))
+		}
+		args = append(args, "--table", strings.TrimSpace(formatDMTable(table)))
+		if _, stderr, err := cmd("dmsetup", args...); err != nil {
+			return fmt.Errorf("dmsetup create %v: %v (%v)", name, err, strings.TrimSpace(stderr))
+		}
+		log.Printf("Create partition mapping: %v\n", part.Path)
+	}
+	cmd("udevadm", "settle")
+	return nil
+}
+
+/*
+Reread partition table after change: partprobe for usual disks, update of partition mappings for device mapper disks.
+
+Перечитывает таблицу разделов после изменения: partprobe для обычных дисков, обновление разделов для дисков device
+mapper.
+*/
+func partitionsReread(disk *diskInfo) {
+	if _, ok := readDMInfo(disk.Major, disk.Minor); disk.Major == 0 || !ok {
+		cmd("partprobe", disk.Path)
+		return
+	}
+	if err := kpartxUpdate(disk.Path); err != nil {
+		log.Println("Can't update partitions of device mapper disk:", disk.Path, err)
+	}
+}
@@ -0,0 +1,118 @@
+package fsextender
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Loop device ioctl from linux/loop.h: reread size of backing file.
+// ioctl loop-устройства из linux/loop.h: перечитать размер файла.
+var loop_SET_CAPACITY = ioc(0, 'L', 7, 0)
+
+// Loop device grow by 4KiB blocks, as usual block of filesystem.
+// Loop-устройство растет блоками по 4KiB, как обычный блок файловой системы.
+const loop_ALIGN = 4096
+
+// Backing file of loop device.
+// Файл loop-устройства.
+type loopInfo struct {
+	File      string
+	Offset    uint64 // Start of device in file. Начало устройства в файле
+	SizeLimit uint64 // 0 - device take all file to end. 0 - устройство занимает весь файл до конца
+}
+
+func readLoopInfo(major, minor int) (loop loopInfo, ok bool) {
+	return readLoopInfoDir(fmt.Sprintf("/sys/dev/block/%v:%v/loop", major, minor))
+}
+
+// dir - loop directory of device in sysfs: /sys/block/loop0/loop
+// dir - папка loop устройства в sysfs: /sys/block/loop0/loop
+func readLoopInfoDir(dir string) (loop loopInfo, ok bool) {
+	read := func(name string) string {
+		content, _ := ioutil.ReadFile(filepath.Join(dir, name))
+		return strings.TrimSpace(string(content))
+	}
+	loop.File = read("backing_file")
+	if loop.File == "" {
+		return loop, false
+	}
+	loop.Offset, _ = parseUint(read("offset"))
+	loop.SizeLimit, _ = parseUint(read("sizelimit"))
+	return loop, true
+}
+
+/*
+Free space for loop device: part of file after end of device and free space of host filesystem. Space of host
+filesystem counted even for sparse file, so the file can be filled later.
+
+Свободное место для loop-устройства: часть файла после конца устройства и свободное место файловой системы, на которой
+лежит файл. Место файловой системы учитывается и для разреженного файла, чтобы его можно было заполнить позже.
+*/
+func loopFreeSpace(loop loopInfo, size uint64) (uint64, error) {
+	stat, err := os.Stat(loop.File)
+	if err != nil {
+		return 0, err
+	}
+	var fsStat syscall.Statfs_t
+	if err = syscall.Statfs(loop.File, &fsStat); err != nil {
+		return 0, err
+	}
+	free := fsStat.Bavail * uint64(fsStat.Bsize)
+	if end := loop.Offset + size; uint64(stat.Size()) > end {
+		free += uint64(stat.Size()) - end
+	}
+	return free / loop_ALIGN * loop_ALIGN, nil
+}
+
+/*
+Grow backing file of loop device to item.Size + item.FreeSpace and reread capacity of the device. File grows sparse or
+with allocated space (item.LoopAllocate). Return new size of device.
+
+Увеличивает файл loop-устройства до item.Size + item.FreeSpace и перечитывает размер устройства. Файл растет
+разреженным или с выделенным местом (item.LoopAllocate). Возвращает новый размер устройства.
+*/
+func loopExtend(item storageItem) (newSize uint64, err error) {
+	major, minor := getMajorMinor(item.Path)
+	loop, ok := readLoopInfo(major, minor)
+	if !ok || loop.File != item.LoopFile {
+		return 0, fmt.Errorf("Backing file of loop device changed: %v (%v)", item.Path, loop.File)
+	}
+	if loop.SizeLimit != 0 {
+		return 0, fmt.Errorf("Size of loop device is limited: %v", item.Path)
+	}
+
+	file, err := os.OpenFile(loop.File, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	fileSize := loop.Offset + item.Size + item.FreeSpace
+	if uint64(stat.Size()) < fileSize {
+		if item.LoopAllocate {
+			err = syscall.Fallocate(int(file.Fd()), 0, stat.Size(), int64(fileSize)-stat.Size())
+		} else {
+			err = file.Truncate(int64(fileSize))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("Can't grow backing file %v: %v", loop.File, err)
+		}
+	}
+
+	device, err := os.Open(item.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer device.Close()
+	if err = ioctl(device, loop_SET_CAPACITY, nil); err != nil {
+		return 0, fmt.Errorf("Can't set capacity of loop device %v: %v", item.Path, err)
+	}
+	return getDiskSize(item.Path), nil
+}
@@ -0,0 +1,638 @@
+package fsextender
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+/*
+Calc how many extents can be added to striped LV. Every stripe of new segment have to be placed on separate PV.
+free - count of free extents on every PV of volume group.
+Return count of logical extents (for all stripes).
+
+Вычисляет сколько экстентов можно добавить к LV с чередованием (striped). Каждая полоса нового сегмента должна
+располагаться на отдельном PV.
+free - количество свободных экстентов на каждом PV группы.
+Возвращает количество логических экстентов (для всех полос вместе).
+*/
+func lvmStripedUsable(free []uint64, stripes uint64) uint64 {
+	return lvmStripeLen(free, stripes) * stripes
+}
+
+// Max length of stripe (in extents), which can be placed on PVs with free extents.
+// Максимальная длина полосы (в экстентах), которую можно разместить на PV со свободными экстентами.
+func lvmStripeLen(free []uint64, stripes uint64) uint64 {
+	if stripes == 0 {
+		return 0
+	}
+	var sum uint64
+	for _, f := range free {
+		sum += f
+	}
+
+	// Every PV can give to one stripe min(f, stripeLen) extents. Find max stripeLen, which can be placed.
+	// Каждый PV может отдать в полосу не больше min(f, stripeLen) экстентов. Ищем максимальную длину полосы, которую
+	// можно разместить.
+	canPlace := func(stripeLen uint64) bool {
+		var placed uint64
+		for _, f := range free {
+			if f < stripeLen {
+				placed += f
+			} else {
+				placed += stripeLen
+			}
+		}
+		return placed >= stripeLen*stripes
+	}
+	stripeLen := uint64(sort.Search(int(sum/stripes)+1, func(i int) bool { return !canPlace(uint64(i)) }))
+	if stripeLen > 0 {
+		stripeLen--
+	}
+	return stripeLen
+}
+
+// Return true for LV segment types, which keep images on separate PVs.
+// Возвращает true для типов сегментов LV, образы которых хранятся на отдельных PV.
+func lvmIsRaid(segType string) bool {
+	return strings.HasPrefix(segType, "raid") || segType == "mirror"
+}
+
+// Return true for LV segment types of LV with attached cache.
+// Возвращает true для типов сегментов LV с подключенным кешем.
+func lvmIsCache(segType string) bool {
+	return segType == "cache" || segType == "writecache"
+}
+
+// Count of images, which store data (not parity or mirror copy).
+// Количество образов, хранящих данные (а не четность или зеркальную копию).
+func lvmRaidDataImages(segType string, images int) uint64 {
+	switch {
+	case segType == "raid1" || segType == "mirror":
+		return 1
+	case strings.HasPrefix(segType, "raid4") || strings.HasPrefix(segType, "raid5"):
+		images -= 1
+	case strings.HasPrefix(segType, "raid6"):
+		images -= 2
+	case segType == "raid10":
+		images /= 2
+	}
+	if images < 1 {
+		return 0
+	}
+	return uint64(images)
+}
+
+// Path of disk, where device placed. For whole disk device return the path.
+// Путь к диску, на котором расположено устройство. Для устройства, занимающего весь диск, возвращается его путь.
+func lvmPVDisk(path string) string {
+	major, minor := getMajorMinor(path)
+	if getTypeByMajorMinor(major, minor) == type_PARTITION {
+		if diskPath, _, err := extractPartNumber(path); err == nil {
+			return diskPath
+		}
+	}
+	return path
+}
+
+/*
+Calc how many extents can be added to every image of raid/mirror LV. New extents of every image have to be placed on
+disks, which doesn't contain other images of the LV.
+pvs - PVs of volume group with free space in extents.
+images - PVs of every image.
+diskOf - return disk of PV.
+
+Вычисляет сколько экстентов можно добавить к каждому образу raid/mirror LV. Новые экстенты каждого образа должны
+располагаться на дисках, на которых нет других образов этого LV.
+pvs - PV группы томов со свободным местом в экстентах.
+images - PV каждого образа.
+diskOf - возвращает диск PV.
+*/
+func lvmRaidUsable(pvs []lvmPV, images [][]string, diskOf func(string) string) uint64 {
+	_, capacity := lvmRaidDisks(pvs, images, diskOf)
+	if len(capacity) == 0 {
+		return 0
+	}
+	res := capacity[0]
+	for _, c := range capacity {
+		if c < res {
+			res = c
+		}
+	}
+	return res
+}
+
+/*
+Assign disks to images of raid/mirror LV: disks, which contain image, belong to it, disks without images are given to
+images with least space. Return image of every disk and free space of every image (in extents).
+
+Распределяет диски по образам raid/mirror LV: диски, на которых есть образ, принадлежат ему, диски без образов
+отдаются образам, у которых меньше всего места. Возвращает образ каждого диска и свободное место каждого образа (в
+экстентах).
+*/
+func lvmRaidDisks(pvs []lvmPV, images [][]string, diskOf func(string) string) (diskImage map[string]int, capacity []uint64) {
+	diskImage = make(map[string]int)
+	if len(images) == 0 {
+		return diskImage, nil
+	}
+
+	// Disks, which already contain the image. If disk contain many images - it belong to first of them.
+	// Диски, на которых уже есть образ. Если на диске несколько образов - он принадлежит первому из них.
+	for image, imagePVs := range images {
+		for _, pv := range imagePVs {
+			disk := diskOf(pv)
+			if _, ok := diskImage[disk]; !ok {
+				diskImage[disk] = image
+			}
+		}
+	}
+
+	diskFree := make(map[string]uint64)
+	var disks []string
+	for _, pv := range pvs {
+		disk := diskOf(pv.Path)
+		if _, ok := diskFree[disk]; !ok {
+			disks = append(disks, disk)
+		}
+		diskFree[disk] += pv.Free
+	}
+
+	capacity = make([]uint64, len(images))
+	var freeDisks []string
+	for _, disk := range disks {
+		if image, ok := diskImage[disk]; ok {
+			capacity[image] += diskFree[disk]
+		} else if diskFree[disk] > 0 {
+			freeDisks = append(freeDisks, disk)
+		}
+	}
+
+	// Give disks without images to the images with least space. Begin from large disks.
+	// Отдаем диски без образов тем образам, у которых меньше всего места. Начинаем с больших дисков.
+	sort.Stable(sort.Reverse(disksByFree{freeDisks, diskFree}))
+	for _, disk := range freeDisks {
+		minImage := 0
+		for image := range capacity {
+			if capacity[image] < capacity[minImage] {
+				minImage = image
+			}
+		}
+		capacity[minImage] += diskFree[disk]
+		diskImage[disk] = minImage
+	}
+	return diskImage, capacity
+}
+
+type disksByFree struct {
+	disks []string
+	free  map[string]uint64
+}
+
+func (arr disksByFree) Len() int           { return len(arr.disks) }
+func (arr disksByFree) Less(i, j int) bool { return arr.free[arr.disks[i]] < arr.free[arr.disks[j]] }
+func (arr disksByFree) Swap(i, j int)      { arr.disks[i], arr.disks[j] = arr.disks[j], arr.disks[i] }
+
+/*
+Return PVs of volume group with free space, which will be after execute plan (in bytes).
+vgIndex - index of volume group in storage.
+indexes - index of every PV in storage.
+
+Возвращает PV группы томов со свободным местом, которое будет после выполнения плана (в байтах).
+vgIndex - индекс группы томов в storage.
+indexes - индекс каждого PV в storage.
+*/
+func lvmPlanPVFree(storage []storageItem, vgIndex int) (res []lvmPV, indexes []int) {
+	// Growth of partitions under PVs
+	// Рост разделов под PV
+	partitionGrowth := make(map[int]uint64)
+	for _, item := range storage {
+		if item.Child != -1 && (item.Type == type_PARTITION || item.Type == type_PARTITION_NEW) {
+			partitionGrowth[item.Child] += item.FreeSpace
+		}
+	}
+
+	for i, item := range storage {
+		if item.Child != vgIndex {
+			continue
+		}
+		var free uint64
+		switch {
+		case item.Type == type_LVM_PV:
+			free = item.LVMPVFree + item.FreeSpace + partitionGrowth[i]
+		case item.Type == type_SKIP && item.OldType == type_LVM_PV:
+			free = item.LVMPVFree
+		case item.Type == type_LVM_PV_ADD:
+			free = item.Size
+		case item.Type == type_LVM_PV_NEW:
+			free = lvmPVCalcSize(partitionGrowth[i], item.LVMPVGeometry, item.LVMExtentSize)
+		default:
+			continue
+		}
+		res = append(res, lvmPV{Path: item.Path, Free: free})
+		indexes = append(indexes, i)
+	}
+	return res, indexes
+}
+
+// Free space of PV, which it has without extend (in bytes).
+// Свободное место PV, которое у него есть без расширения (в байтах).
+func lvmPVCurrentFree(item storageItem) uint64 {
+	if item.Type == type_LVM_PV || item.Type == type_SKIP && item.OldType == type_LVM_PV {
+		return item.LVMPVFree
+	}
+	return 0
+}
+
+// Return PVs of volume group with free space now.
+// Возвращает PV группы томов со свободным местом в данный момент.
+func lvmVGPVs(vgName string) (res []lvmPV) {
+	for _, pv := range getLvmPV() {
+		if pv.VolumeGroup == vgName {
+			res = append(res, pv)
+		}
+	}
+	return res
+}
+
+// Convert free space of PVs from bytes to extents.
+// Переводит свободное место PV из байт в экстенты.
+func lvmPVFreeExtents(pvs []lvmPV, extentSize uint64) []lvmPV {
+	res := make([]lvmPV, len(pvs))
+	for i := range pvs {
+		res[i] = pvs[i]
+		res[i].Free = pvs[i].Free / extentSize
+	}
+	return res
+}
+
+func lvmPVFreeList(pvs []lvmPV) []uint64 {
+	res := make([]uint64, len(pvs))
+	for i := range pvs {
+		res[i] = pvs[i].Free
+	}
+	return res
+}
+
+/*
+Return count of logical extents, which can be added to striped or raid LV. pvs - PVs of volume group with free space
+in extents.
+
+Возвращает количество логических экстентов, которые можно добавить к LV с чередованием или raid. pvs - PV группы
+томов со свободным местом в экстентах.
+*/
+func lvmLVUsableExtents(lv storageItem, pvs []lvmPV) uint64 {
+	if lvmIsCache(lv.LVMSegType) {
+		// Origin of cached LV have not to be extended on PVs of cache
+		// Исходный LV не должен расширяться на PV кеша
+		var res uint64
+		for _, pv := range lvmLVOriginPVs(lv, pvs) {
+			res += pv.Free
+		}
+		return res
+	}
+	if lvmIsRaid(lv.LVMSegType) {
+		return lvmRaidUsable(pvs, lv.LVMImagePVs, lvmPVDisk) * lvmRaidDataImages(lv.LVMSegType, len(lv.LVMImagePVs))
+	}
+	return lvmStripedUsable(lvmPVFreeList(pvs), lv.LVMStripes)
+}
+
+/*
+Return how many extents of every PV will be used by extend of striped, raid or cached LV.
+pvs - PVs of volume group with free space in extents.
+current - free extents of every PV without extend. They are used first.
+
+Возвращает сколько экстентов каждого PV будет использовано при расширении LV с чередованием, raid или кешем.
+pvs - PV группы томов со свободным местом в экстентах.
+current - свободные экстенты каждого PV без расширения. Они используются в первую очередь.
+*/
+func lvmLVPVUsage(lv storageItem, pvs []lvmPV, current []uint64) []uint64 {
+	limit := make([]uint64, len(pvs))
+	switch {
+	case lvmIsCache(lv.LVMSegType):
+		originPVs := make(map[string]bool)
+		for _, pv := range lvmLVOriginPVs(lv, pvs) {
+			originPVs[pv.Path] = true
+		}
+		for i, pv := range pvs {
+			if originPVs[pv.Path] {
+				limit[i] = pv.Free
+			}
+		}
+		return lvmPVAllocate(lvmLVUsableExtents(lv, pvs), limit, current)
+	case lvmIsRaid(lv.LVMSegType):
+		// Every image takes same count of extents from its disks
+		// Каждый образ берет одинаковое количество экстентов со своих дисков
+		diskImage, capacity := lvmRaidDisks(pvs, lv.LVMImagePVs, lvmPVDisk)
+		usage := make([]uint64, len(pvs))
+		need := lvmRaidUsable(pvs, lv.LVMImagePVs, lvmPVDisk)
+		for image := range capacity {
+			for i, pv := range pvs {
+				if owner, ok := diskImage[lvmPVDisk(pv.Path)]; ok && owner == image {
+					limit[i] = pv.Free
+				} else {
+					limit[i] = 0
+				}
+			}
+			for i, used := range lvmPVAllocate(need, limit, current) {
+				usage[i] += used
+			}
+		}
+		return usage
+	default:
+		// PV can't give to new segment more then length of stripe
+		// PV не может отдать новому сегменту больше длины полосы
+		stripeLen := lvmStripeLen(lvmPVFreeList(pvs), lv.LVMStripes)
+		for i, pv := range pvs {
+			limit[i] = pv.Free
+			if limit[i] > stripeLen {
+				limit[i] = stripeLen
+			}
+		}
+		return lvmPVAllocate(stripeLen*lv.LVMStripes, limit, current)
+	}
+}
+
+/*
+Distribute need extents between PVs. Free extents, which PVs have already, are taken first, then extents from growth
+of PVs. PV can't give more then its limit.
+
+Распределяет need экстентов между PV. Сначала берутся свободные экстенты, которые у PV уже есть, потом экстенты из
+роста PV. PV не может отдать больше своего limit.
+*/
+func lvmPVAllocate(need uint64, limit, current []uint64) []uint64 {
+	usage := make([]uint64, len(limit))
+	take := func(i int, available uint64) {
+		if available > need {
+			available = need
+		}
+		usage[i] += available
+		need -= available
+	}
+	for i := range limit {
+		if current[i] < limit[i] {
+			take(i, current[i])
+		} else {
+			take(i, limit[i])
+		}
+	}
+	for i := range limit {
+		take(i, limit[i]-usage[i])
+	}
+	return usage
+}
+
+// Return PVs, which can be used for extend origin of cached LV: all PVs except PVs of the cache.
+// Возвращает PV, которые можно использовать для расширения исходного LV под кешем: все PV, кроме PV кеша.
+func lvmLVOriginPVs(lv storageItem, pvs []lvmPV) (res []lvmPV) {
+pvLoop:
+	for _, pv := range pvs {
+		for _, cachePV := range lv.LVMCachePVs {
+			if pv.Path == cachePV {
+				continue pvLoop
+			}
+		}
+		res = append(res, pv)
+	}
+	return res
+}
+
+// Return true if LV can't use all free space of volume group.
+// Возвращает true, если LV не может использовать все свободное место группы томов.
+func lvmLVLayoutLimited(lv storageItem) bool {
+	return lvmIsRaid(lv.LVMSegType) || lvmIsCache(lv.LVMSegType) || lv.LVMStripes > 1
+}
+
+/*
+Limit extend of striped and raid LVs by space, which can be used by the layout. If raid LV can't use free space at all
+(for example filter leave too few disks) - skip the LV and extend of its PVs. Growth of PVs and partitions under them
+is limited by space, which the LV can use on every PV.
+
+Ограничивает расширение LV с чередованием и raid объемом, который может использовать их структура. Если raid LV
+совсем не может использовать свободное место (например фильтр оставил слишком мало дисков) - отменяет расширение LV
+и его PV. Рост PV и разделов под ними ограничивается местом, которое LV может использовать на каждом PV.
+*/
+func lvmPlanLayout(storage []storageItem) {
+	for lvIndex := range storage {
+		lv := &storage[lvIndex]
+		if lv.Type != type_LVM_LV || !lvmLVLayoutLimited(*lv) || lv.LVMExtentSize == 0 {
+			continue
+		}
+		for vgIndex, vg := range storage {
+			if vg.Type != type_LVM_GROUP || vg.Child != lvIndex {
+				continue
+			}
+			pvs, indexes := lvmPlanPVFree(storage, vgIndex)
+			var totalFree, pvsWithFree uint64
+			disksWithFree := make(map[string]bool)
+			for _, pv := range pvs {
+				totalFree += pv.Free
+				if pv.Free >= lv.LVMExtentSize {
+					pvsWithFree++
+					disksWithFree[lvmPVDisk(pv.Path)] = true
+				}
+			}
+			pvExtents := lvmPVFreeExtents(pvs, lv.LVMExtentSize)
+			usable := lvmLVUsableExtents(*lv, pvExtents) * lv.LVMExtentSize
+
+			if lvmIsRaid(lv.LVMSegType) && usable == 0 && totalFree > 0 {
+				reason := fmt.Sprintf("%v LV needs free space on %v separate disks, but plan has free space on %v disks only.",
+					lv.LVMSegType, len(lv.LVMImagePVs), len(disksWithFree))
+				lvmPlanSkipVG(storage, vgIndex, reason)
+				skipStorageItem(lv, reason)
+				continue
+			}
+			if !lvmIsRaid(lv.LVMSegType) && pvsWithFree < lv.LVMStripes {
+				log.Printf("Striped LV %v needs free space on %v PVs, but plan has free space on %v PVs only.\n",
+					lv.Path, lv.LVMStripes, pvsWithFree)
+			}
+			lv.MaxSize = lv.Size + usable
+			limitFreeSpace(lv)
+
+			current := make([]uint64, len(pvs))
+			for i, pvIndex := range indexes {
+				current[i] = lvmPVCurrentFree(storage[pvIndex]) / lv.LVMExtentSize
+			}
+			var used uint64
+			for i, extents := range lvmLVPVUsage(*lv, pvExtents, current) {
+				used += extents * lv.LVMExtentSize
+				lvmPlanLimitPV(storage, indexes[i], pvs[i].Free, extents*lv.LVMExtentSize, lv.LVMExtentSize)
+			}
+			if used < totalFree {
+				log.Printf("LV %v (%v) can use %v of %v free space on its PVs.\n", lv.Path, lv.LVMSegType,
+					formatSize(used), formatSize(totalFree))
+			}
+		}
+	}
+}
+
+/*
+Limit growth of PV and partition under it, if LV can use only part of free space of the PV. Space, which can't be used,
+is cut from growth of partition. PV without usable growth is skipped with the partition.
+free - free space of PV after execute plan, usable - space of PV, which LV can use (in bytes).
+
+Ограничивает рост PV и раздела под ним, если LV может использовать только часть свободного места PV. Место, которое не
+может быть использовано, вычитается из роста раздела. PV без используемого роста отменяется вместе с разделом.
+free - свободное место PV после выполнения плана, usable - место PV, которое может использовать LV (в байтах).
+*/
+func lvmPlanLimitPV(storage []storageItem, pvIndex int, free, usable, extentSize uint64) {
+	pv := &storage[pvIndex]
+	if usable >= free {
+		return
+	}
+	partIndex := -1
+	for i, item := range storage {
+		if item.Child == pvIndex && (item.Type == type_PARTITION || item.Type == type_PARTITION_NEW) {
+			partIndex = i
+		}
+	}
+
+	switch pv.Type {
+	case type_LVM_PV:
+		if usable <= pv.LVMPVFree {
+			reason := fmt.Sprintf("LV can't use growth of PV %v.", pv.Path)
+			if partIndex != -1 {
+				skipStorageItem(&storage[partIndex], reason)
+			}
+			if pv.FreeSpace > 0 {
+				skipStorageItem(pv, reason)
+			}
+			return
+		}
+		if partIndex == -1 {
+			return
+		}
+		// Cut whole extents only: PV is rounded to extents
+		// Отрезаем только целые экстенты: размер PV округляется до экстентов
+		part := &storage[partIndex]
+		cut := (free - usable) / extentSize * extentSize
+		if cut > part.FreeSpace {
+			cut = part.FreeSpace
+		}
+		part.MaxSize = part.Size + part.FreeSpace - cut
+		limitFreeSpace(part)
+	case type_LVM_PV_ADD:
+		if usable == 0 {
+			skipStorageItem(pv, fmt.Sprintf("LV can't use PV %v.", pv.Path))
+		}
+	case type_LVM_PV_NEW:
+		if partIndex == -1 {
+			return
+		}
+		part := &storage[partIndex]
+		if usable == 0 {
+			reason := fmt.Sprintf("LV can't use PV %v.", pv.Path)
+			skipStorageItem(part, reason)
+			skipStorageItem(pv, reason)
+			return
+		}
+		// Create smaller partition, aligned to MiB
+		// Создаем раздел меньшего размера, выровненный по MiB
+		size := usable + pv.LVMPVGeometry.PEStart + pv.LVMPVGeometry.MDAEnd
+		size = (size + lvm_PARTITION_ALIGN - 1) / lvm_PARTITION_ALIGN * lvm_PARTITION_ALIGN
+		if size >= part.FreeSpace {
+			return
+		}
+		part.MaxSize = size
+		limitFreeSpace(part)
+		part.Partition.LastByte = part.Partition.FirstByte + part.FreeSpace - 1
+	}
+}
+
+// Alignment of new partition, which limited by usable space of LV.
+// Выравнивание нового раздела, размер которого ограничен используемым местом LV.
+const lvm_PARTITION_ALIGN = 1024 * 1024
+
+// Skip extend and create of PVs of volume group and partitions under them.
+// Отменяет расширение и создание PV группы томов и разделов под ними.
+func lvmPlanSkipVG(storage []storageItem, vgIndex int, reason string) {
+	for pvIndex := range storage {
+		pv := &storage[pvIndex]
+		if pv.Child != vgIndex {
+			continue
+		}
+		switch pv.Type {
+		case type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
+		default:
+			continue
+		}
+		for partIndex := range storage {
+			part := &storage[partIndex]
+			if part.Child == pvIndex && (part.Type == type_PARTITION || part.Type == type_PARTITION_NEW) {
+				skipStorageItem(part, reason)
+			}
+		}
+		skipStorageItem(pv, reason)
+	}
+}
+
+func skipStorageItem(item *storageItem, reason string) {
+	item.OldType = item.Type
+	item.Type = type_SKIP
+	item.SkipReason = reason
+}
+
+// Max size of thin pool metadata, as in LVM (DEFAULT_THIN_POOL_MAX_METADATA_SIZE).
+// Максимальный размер метаданных пула тонких томов, как в LVM (DEFAULT_THIN_POOL_MAX_METADATA_SIZE).
+const lvm_THIN_METADATA_MAX = 255 * (1<<14 - 64) * 4 * 1024
+
+/*
+Split free space of volume group between data and metadata of thin pool in proportion of their current sizes.
+Metadata can't be greater then lvm_THIN_METADATA_MAX.
+
+Делит свободное место группы томов между данными и метаданными пула тонких томов пропорционально их текущим
+размерам. Метаданные не могут быть больше lvm_THIN_METADATA_MAX.
+*/
+func lvmThinPoolGrowth(pool storageItem, free uint64) (data, meta uint64) {
+	extentSize := pool.LVMExtentSize
+	if extentSize == 0 {
+		extentSize = 1
+	}
+	if pool.Size+pool.LVMMetaSize > 0 {
+		meta = uint64(float64(free) * float64(pool.LVMMetaSize) / float64(pool.Size+pool.LVMMetaSize))
+	}
+	switch {
+	case pool.LVMMetaSize >= lvm_THIN_METADATA_MAX:
+		meta = 0
+	case pool.LVMMetaSize+meta > lvm_THIN_METADATA_MAX:
+		meta = lvm_THIN_METADATA_MAX - pool.LVMMetaSize
+	}
+	meta = meta / extentSize * extentSize
+	data = (free - meta) / extentSize * extentSize
+	return data, meta
+}
+
+// Return growth of virtual size of LV when its thin pool grow. For other LVs return poolGrowth.
+// Возвращает рост виртуального размера LV при росте его пула. Для остальных LV возвращает poolGrowth.
+func lvmThinLVGrowth(lv storageItem, poolGrowth uint64) uint64 {
+	if lv.Type != type_LVM_LV || lv.LVMPool == "" {
+		return poolGrowth
+	}
+	return poolGrowth / 100 * (100 + lv.LVMOvercommit)
+}
+
+/*
+Set free space of thin LVs by size of their pools. Virtual size of thin LV can exceed pool size by overcommit percents.
+
+Устанавливает свободное место тонких LV по размеру их пулов. Виртуальный размер тонкого LV может превышать размер пула
+на overcommit процентов.
+*/
+func lvmPlanThin(storage []storageItem, overcommit uint64) {
+	for _, pool := range storage {
+		if pool.Type != type_LVM_THIN_POOL || pool.Child == -1 {
+			continue
+		}
+		lv := &storage[pool.Child]
+		if lv.Type != type_LVM_LV || lv.LVMPool == "" {
+			continue
+		}
+		lv.LVMOvercommit = overcommit
+		target := lvmThinLVGrowth(*lv, pool.Size)
+		if lv.LVMExtentSize > 0 {
+			target = target / lv.LVMExtentSize * lv.LVMExtentSize
+		}
+		if target > lv.Size {
+			lv.FreeSpace = target - lv.Size
+		}
+	}
+}
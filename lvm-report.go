@@ -0,0 +1,238 @@
+package fsextender
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+Snapshot of LVM state from JSON reports of pvs, vgs and lvs. Names of VG and LV aren't parsed from text, so they can
+contain any characters.
+
+Снимок состояния LVM из JSON-отчетов pvs, vgs и lvs. Имена VG и LV не разбираются из текста, поэтому могут содержать
+любые символы.
+*/
+type lvmSnapshot struct {
+	PVs  []lvmReportPV
+	VGs  []lvmReportVG
+	LVs  []lvmReportLV
+	Segs []lvmReportSeg
+}
+
+type lvmReportPV struct {
+	Name     string    `json:"pv_name"`
+	VGName   string    `json:"vg_name"`
+	Size     lvmNumber `json:"pv_size"`
+	Free     lvmNumber `json:"pv_free"`
+	UUID     string    `json:"pv_uuid"`
+	VGUUID   string    `json:"vg_uuid"`
+	PEStart  lvmNumber `json:"pe_start"`
+	MDASize  lvmNumber `json:"pv_mda_size"`
+	MDACount lvmNumber `json:"pv_mda_count"`
+}
+
+type lvmReportVG struct {
+	Name       string    `json:"vg_name"`
+	Size       lvmNumber `json:"vg_size"`
+	Free       lvmNumber `json:"vg_free"`
+	ExtentSize lvmNumber `json:"vg_extent_size"`
+	UUID       string    `json:"vg_uuid"`
+}
+
+type lvmReportLV struct {
+	VGName      string    `json:"vg_name"`
+	Name        string    `json:"lv_name"` // Hidden LVs in brackets: [lv_rimage_0]. Скрытые LV в квадратных скобках
+	KernelMajor string    `json:"lv_kernel_major"`
+	KernelMinor string    `json:"lv_kernel_minor"`
+	Size        lvmNumber `json:"lv_size"`
+	UUID        string    `json:"lv_uuid"`
+}
+
+type lvmReportSeg struct {
+	VGName     string    `json:"vg_name"`
+	LVName     string    `json:"lv_name"`
+	SegType    string    `json:"segtype"`
+	Stripes    lvmNumber `json:"stripes"`
+	StripeSize lvmNumber `json:"stripe_size"`
+	PoolLV     string    `json:"pool_lv"`
+	MetaSize   lvmNumber `json:"lv_metadata_size"`
+	CacheMode  string    `json:"cache_mode"`
+	Devices    string    `json:"devices"`
+}
+
+// Numbers in JSON report of LVM are strings. Empty string - 0.
+// Числа в JSON-отчете LVM - строки. Пустая строка - 0.
+type lvmNumber uint64
+
+func (this *lvmNumber) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	s = strings.TrimSuffix(strings.TrimSpace(s), "B")
+	if s == "" {
+		*this = 0
+		return nil
+	}
+	res, err := parseUint(s)
+	*this = lvmNumber(res)
+	return err
+}
+
+// Path of LV as VolumeGroup/VolumeName, without brackets of hidden LV.
+// Путь LV в виде VolumeGroup/VolumeName, без скобок скрытого LV.
+func (this lvmReportLV) Path() string {
+	return this.VGName + "/" + strings.Trim(this.Name, "[]")
+}
+
+// Major and minor numbers of active LV. ok == false for inactive LV.
+// Номера устройства активного LV. ok == false для неактивного LV.
+func (this lvmReportLV) MajorMinor() (major, minor int, ok bool) {
+	major, err := strconv.Atoi(this.KernelMajor)
+	if err != nil || major < 0 {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(this.KernelMinor)
+	if err != nil || minor < 0 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+func (this lvmReportSeg) Path() string {
+	return this.VGName + "/" + strings.Trim(this.LVName, "[]")
+}
+
+func (this *lvmSnapshot) PV(path string) (pv lvmReportPV, ok bool) {
+	for _, pv = range this.PVs {
+		if pv.Name == path {
+			return pv, true
+		}
+	}
+	return pv, false
+}
+
+func (this *lvmSnapshot) VG(name string) (vg lvmReportVG, ok bool) {
+	for _, vg = range this.VGs {
+		if vg.Name == name {
+			return vg, true
+		}
+	}
+	return vg, false
+}
+
+// path - VolumeGroup/VolumeName
+func (this *lvmSnapshot) LV(path string) (lv lvmReportLV, ok bool) {
+	for _, lv = range this.LVs {
+		if lv.Path() == path {
+			return lv, true
+		}
+	}
+	return lv, false
+}
+
+// Report of lvm command: {"report": [{"pv": [...]}]}
+// Отчет команды lvm: {"report": [{"pv": [...]}]}
+type lvmJSONReport struct {
+	Report []struct {
+		PV  []lvmReportPV  `json:"pv"`
+		VG  []lvmReportVG  `json:"vg"`
+		LV  []lvmReportLV  `json:"lv"`
+		Seg []lvmReportSeg `json:"seg"`
+	} `json:"report"`
+}
+
+func parseLvmReport(data []byte) (report lvmJSONReport, err error) {
+	err = json.Unmarshal(data, &report)
+	return
+}
+
+// Read JSON report of lvm command. args - command and its options without report format options.
+// Читает JSON-отчет команды lvm. args - команда и ее параметры без параметров формата отчета.
+func lvmReadReport(command string, args ...string) (report lvmJSONReport) {
+	args = append(args, "--reportformat", "json", "--units", "b", "--nosuffix")
+	res, stderr, err := cmd(command, lvmArgs(args)...)
+	if err != nil {
+		log.Printf("Can't read LVM report: %v %v (%v)\n%v\n", command, strings.Join(args, " "), err, stderr)
+		return
+	}
+	report, err = parseLvmReport([]byte(res))
+	if err != nil {
+		log.Printf("Can't parse LVM report: %v %v (%v)\n", command, strings.Join(args, " "), err)
+	}
+	return
+}
+
+func lvmLoadSnapshot() *lvmSnapshot {
+	snapshot := &lvmSnapshot{}
+	for _, report := range lvmReadReport("pvs", "-o", "pv_name,vg_name,pv_size,pv_free,pv_uuid,vg_uuid,pe_start,pv_mda_size,pv_mda_count").Report {
+		snapshot.PVs = append(snapshot.PVs, report.PV...)
+	}
+	for _, report := range lvmReadReport("vgs", "-o", "vg_name,vg_size,vg_free,vg_extent_size,vg_uuid").Report {
+		snapshot.VGs = append(snapshot.VGs, report.VG...)
+	}
+	for _, report := range lvmReadReport("lvs", "-a", "-o",
+		"vg_name,lv_name,lv_kernel_major,lv_kernel_minor,lv_size,lv_uuid").Report {
+		snapshot.LVs = append(snapshot.LVs, report.LV...)
+	}
+	for _, report := range lvmReadReport("lvs", "-a", "--segments", "-o",
+		"vg_name,lv_name,segtype,stripes,stripe_size,pool_lv,lv_metadata_size,cache_mode,devices").Report {
+		snapshot.Segs = append(snapshot.Segs, report.Seg...)
+	}
+	return snapshot
+}
+
+/*
+Snapshot of LVM for current run. It loaded once and reloaded only after commands, which change LVM (see lvmCmd).
+
+Снимок LVM для текущего запуска. Загружается один раз и перезагружается только после команд, изменяющих LVM (см. lvmCmd).
+*/
+type lvmSnapshotCache struct {
+	mutex    sync.Mutex
+	snapshot *lvmSnapshot
+}
+
+var lvmState = &lvmSnapshotCache{}
+
+func (this *lvmSnapshotCache) Get() *lvmSnapshot {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.snapshot == nil {
+		this.snapshot = lvmLoadSnapshot()
+	}
+	return this.snapshot
+}
+
+// Next Get load new snapshot.
+// Следующий Get загрузит новый снимок.
+func (this *lvmSnapshotCache) Invalidate() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.snapshot = nil
+}
+
+func (this *lvmSnapshotCache) Reload() *lvmSnapshot {
+	this.Invalidate()
+	return this.Get()
+}
+
+// Run command, which change LVM, and invalidate snapshot.
+// Выполняет команду, изменяющую LVM, и сбрасывает снимок.
+func lvmCmd(command string, args ...string) (res, errString string, err error) {
+	defer lvmState.Invalidate()
+	return cmd(command, lvmArgs(args)...)
+}
+
+// Config of LVM commands. Image mode sees devices of image only. Empty - config of host.
+// Настройки команд LVM. В режиме образа видны только устройства образа. Пустая строка - настройки хоста.
+var lvmConfig string
+
+func lvmArgs(args []string) []string {
+	if lvmConfig == "" {
+		return args
+	}
+	return append([]string{"--config", lvmConfig}, args...)
+}
@@ -7,8 +7,8 @@ import (
 	"log"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
+	"syscall"
 )
 
 const DEBUG = false
@@ -19,6 +19,21 @@ func Main() int {
 	showReadme := pflag.Bool("readme", false, "Show readme")
 	do := pflag.Bool("do", false, "Execute plan instead of print it")
 	filter := pflag.StringP("filter", "f", FILTER_LVM_ALREADY_PLACED, "filter of disks, which use for partition extends")
+	enable64bit := pflag.Bool("enable-64bit", false, "Enable 64bit feature of unmounted ext4 if it need for extend")
+	thinOvercommit := pflag.Uint64("thin-overcommit", 0, "How many percents virtual size of thin LV can exceed size of its pool")
+	configPath := pflag.String("config", config_DEFAULT_PATH, "Config file with extend policies of mount points")
+	all := pflag.Bool("all", false, "Extend all mount points from config file or all mounted filesystems")
+	savePlanPath := pflag.String("save-plan", "", "Save plan with fingerprints of devices to file")
+	applyPlanPath := pflag.String("apply-plan", "", "Load plan from file and check fingerprints of devices")
+	onlySteps := pflag.String("only", "", "Execute only the steps: indexes or layers, separated by comma")
+	skipSteps := pflag.String("skip", "", "Don't execute the steps: indexes or layers, separated by comma")
+	untilStep := pflag.String("until", "", "Execute steps up to the step (index or layer), include it")
+	jobs := pflag.IntP("jobs", "j", 1, "Count of plan steps, which can be executed concurrently")
+	resizeBackend := pflag.String("resize-backend", backend_AUTO, "Backend of filesystem resize: auto, native, tools")
+	rescan := pflag.Bool("rescan", false, "Rescan capacity of SCSI disks under target before plan")
+	imagePartition := pflag.Uint32("partition", 0, "Partition of image for extend (fsextender image). 0 - last partition")
+	imageLV := pflag.String("lv", "", "LV of image for extend as VG/LV (fsextender image)")
+	guest := pflag.Bool("guest", false, "Target is disk of virtual machine (LV or loop device): extend its last partition")
 	pflag.Parse()
 
 	if *showHelp {
@@ -41,43 +56,208 @@ func Main() int {
 		return 0
 	}
 
-	if pflag.NArg() != 1 || !filepath.IsAbs(pflag.Arg(0)) {
-		printShortUsage()
+	// Flags from command line have priority over config
+	// Параметры командной строки имеют приоритет над файлом настроек
+	setFlags := make(map[string]bool)
+	pflag.Visit(func(f *pflag.Flag) { setFlags[f.Name] = true })
+
+	conf, err := readConfig(*configPath, setFlags["config"])
+	if err != nil {
+		log.Println("Error while read config:", *configPath, err)
 		return 11
 	}
 
-	startPoint := pflag.Arg(0)
-	storage, err := extendScanWays(startPoint)
-	//	fmt.Println("SCAN PLAN:")
-	//	extendPrint(storage)
-	//	fmt.Println()
-	//	fmt.Println()
-	//	fmt.Println()
-	if err != nil {
-		panic(err)
+	needReboot := false
+	runPlan := func(saved savedPlan, showTarget bool) {
+		if *do {
+			if extendDo(saved.Plan, doOptions{Jobs: *jobs, ResizeBackend: *resizeBackend}) {
+				needReboot = true
+			}
+			return
+		}
+		if showTarget {
+			fmt.Println(saved.Target + ":")
+		}
+		extendPrint(saved.Plan)
 	}
-	plan, err := extendPlan(storage, *filter)
-	if err != nil {
-		log.Println("Error while make extend plan:", err)
+
+	switch *resizeBackend {
+	case backend_AUTO, backend_NATIVE, backend_TOOLS:
+	default:
+		printShortUsage()
+		return 11
+	}
+
+	var targets []mountPolicy
+	imageMode := false
+	switch {
+	case *applyPlanPath != "":
+		if *all || pflag.NArg() != 0 || *savePlanPath != "" {
+			printShortUsage()
+			return 11
+		}
+	case pflag.NArg() == 2 && pflag.Arg(0) == "image":
+		// Image is attached temporarily, saved plan can't refer to its devices
+		// Образ подключается временно, сохраненный план не может ссылаться на его устройства
+		if *all || *guest || *savePlanPath != "" {
+			printShortUsage()
+			return 11
+		}
+		image, err := imageAttach(pflag.Arg(1))
+		if err != nil {
+			log.Println("Can't attach image:", pflag.Arg(1), err)
+			return 11
+		}
+		defer image.Detach()
+		target, err := image.Target(*imagePartition, *imageLV)
+		if err != nil {
+			log.Println("Can't find target in image:", pflag.Arg(1), err)
+			return 11
+		}
+		imageMode = true
+		targets = []mountPolicy{conf.policy(target)}
+	case *guest && (*all || *savePlanPath != ""):
+		// Partitions of guest are mapped temporarily, saved plan can't refer to them
+		// Разделы гостя отображаются временно, сохраненный план не может на них ссылаться
+		printShortUsage()
+		return 11
+	case *savePlanPath != "" && *do:
+		// Saved plan have to be reviewed before execute
+		// Сохраненный план должен быть проверен перед выполнением
+		printShortUsage()
+		return 11
+	case *all && pflag.NArg() == 0:
+		targets = conf.targets()
+	case !*all && pflag.NArg() == 1:
+		target, err := resolveStartPoint(pflag.Arg(0))
+		if err != nil {
+			log.Println("Can't resolve start point:", pflag.Arg(0), err)
+			return 11
+		}
+		if *guest {
+			guestTarget, cleanup, err := guestMap(target)
+			if err != nil {
+				log.Println("Can't map partitions of guest disk:", target, err)
+				return 11
+			}
+			defer cleanup()
+			target = guestTarget
+		}
+		targets = []mountPolicy{conf.policy(target)}
+	default:
+		printShortUsage()
 		return 11
 	}
 
-	if *do {
-		if extendDo(plan) {
+	if *applyPlanPath != "" {
+		file, err := loadPlan(*applyPlanPath)
+		if err != nil {
+			log.Println("Can't load plan:", *applyPlanPath, err)
+			return 11
+		}
+		if err = checkPlanFingerprints(file); err != nil {
+			log.Println("Can't apply plan:", err)
+			return 11
+		}
+		for _, saved := range file.Plans {
+			if err = planSelectSteps(saved.Plan, *onlySteps, *skipSteps, *untilStep); err != nil {
+				log.Println("Error while select steps of plan:", err)
+				return 11
+			}
+			runPlan(saved, len(file.Plans) > 1)
+		}
+	}
+
+	// Storage, which already planned for other target. Shared disks and volume groups planned once.
+	// Устройства, уже запланированные для другой цели. Общие диски и группы томов планируются один раз.
+	planned := make(map[string]string)
+	var plans []savedPlan
+	planFailed := false
+	for _, target := range targets {
+		options := target.planOptions()
+		if setFlags["filter"] {
+			options.Filter = *filter
+		}
+		options.Ext4Enable64bit = *enable64bit
+		options.ThinOvercommit = *thinOvercommit
+		if imageMode {
+			options.Layers = imageLayers(options.Layers)
+		}
+
+		storage, err := extendScanWays(target.MountPoint, *guest || imageMode)
+		//	fmt.Println("SCAN PLAN:")
+		//	extendPrint(storage)
+		//	fmt.Println()
+		//	fmt.Println()
+		//	fmt.Println()
+		if err != nil {
+			if !*all {
+				panic(err)
+			}
+			log.Println("Error while scan:", target.MountPoint, err)
+			continue
+		}
+		// Kernel can show old size of disk after it grow in hypervisor. Scan once more if size changed.
+		// Ядро может показывать старый размер диска после его увеличения в гипервизоре. Если размер изменился -
+		// сканируем еще раз.
+		if *rescan && rescanDisks(storageRescanDisks(storage)) {
+			storage, err = extendScanWays(target.MountPoint, *guest || imageMode)
+			if err != nil {
+				log.Println("Error while scan after rescan of disks:", target.MountPoint, err)
+				continue
+			}
+		}
+		// Previous targets can be extended already: error of one target doesn't stop others
+		// Предыдущие цели уже могли быть расширены: ошибка одной цели не останавливает остальные
+		plan, err := extendPlan(storage, options)
+		if err != nil {
+			log.Println("Error while make extend plan:", target.MountPoint, err)
+			planFailed = true
+			continue
+		}
+		if err = planSelectSteps(plan, *onlySteps, *skipSteps, *untilStep); err != nil {
+			log.Println("Error while select steps of plan:", target.MountPoint, err)
+			planFailed = true
+			continue
+		}
+		planSkipShared(plan, planned, target.MountPoint)
+
+		saved := savedPlan{Target: target.MountPoint, Plan: plan}
+		plans = append(plans, saved)
+		runPlan(saved, *all)
+	}
+
+	if *savePlanPath != "" {
+		if err = savePlan(*savePlanPath, plans); err != nil {
+			log.Println("Can't save plan:", *savePlanPath, err)
+			return 11
+		}
+	}
+
+	if planFailed {
+		if needReboot {
 			fmt.Println("NEED REBOOT AND START ME ONCE AGAIN.")
-			return 128
-		} else {
-			fmt.Println("OK")
-			return 0
 		}
+		log.Println("Plan of some targets failed, see errors above")
+		return 11
+	}
+	if !*do {
+		return 0
+	}
+	if needReboot {
+		fmt.Println("NEED REBOOT AND START ME ONCE AGAIN.")
+		return 128
 	} else {
-		extendPrint(plan)
+		fmt.Println("OK")
 		return 0
 	}
 }
 
 func printShortUsage() {
 	fmt.Printf(`Short usage: %v [options] <start_point>
+             %v [options] --all
+             %v [options] --apply-plan=<plan.json>
+             %v [options] image <file.img> [--partition=N | --lv=VG/LV]
 Detect result:
 OK - if extended compele. Return code 0.
 NEED REBOOT AND START ME ONCE AGAIN. - if need reboot and run command with same parameters. Return code 128.
@@ -85,7 +265,7 @@ NEED REBOOT AND START ME ONCE AGAIN. - if need reboot and run command with same
 0 < Code < 128 mean error exit. (Now it print usages and panic only).
 
 Options:
-`, os.Args[0])
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 	pflag.PrintDefaults()
 }
 
@@ -103,6 +283,23 @@ func cmd(cmd string, args ...string) (stdout, errout string, err error) {
 	return bufStd.String(), bufErr.String(), err
 }
 
+/*
+Exit code of command by error of cmd: 0 if err == nil, -1 if command doesn't start or killed by signal.
+
+Код завершения команды по ошибке cmd: 0, если err == nil, -1, если команда не запустилась или убита сигналом.
+*/
+func cmdExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Exited() {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
+
 /*
 execute command with args and return slice of strings.TrimSpace(line). Empty lines removed.
 Возвращает stdout команды, разделенный на строки. У каждой строки пустые символы в начале/конце обрезаны, пустые строки
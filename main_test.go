@@ -1040,7 +1040,7 @@ func TestRecursiveHierarchy(t *testing.T) {
 	defer sudo("vgreduce", LVM_VG_NAME, lvmLV)
 
 	resetProgramState()
-	_, err = extendScanWays(lvmLV)
+	_, err = extendScanWays(lvmLV, false)
 	if err == nil {
 		t.Error("MUST detect hierarchy recursive error")
 	} else {
@@ -1461,3 +1461,199 @@ func TestIssue_18_MBR_ReadErrorBigDisk(t *testing.T) {
 		t.Error(partDiff)
 	}
 }
+
+// Disk grows in "hypervisor": scsi_debug change virtual size, kernel sees it after rescan only.
+func TestExt4RescanScsiDebug(t *testing.T) {
+	const scsiDebug = "/sys/bus/pseudo/drivers/scsi_debug"
+	if _, _, err := sudo("modprobe", "scsi_debug", "dev_size_mb=64", "virtual_gb=1"); err != nil {
+		t.Skip("Can't load scsi_debug:", err)
+	}
+	defer sudo("modprobe", "-r", "scsi_debug")
+	sudo("udevadm", "settle")
+
+	blocks, _ := filepath.Glob(scsiDebug + "/adapter0/host*/target*/*/block/*")
+	if len(blocks) != 1 {
+		t.Fatal("Can't find disk of scsi_debug", blocks)
+	}
+	disk := "/dev/" + filepath.Base(blocks[0])
+
+	sudo("parted", "-s", disk, "mklabel", "gpt")
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "primary", s(GPT_START_BYTE), s(GPT_START_BYTE+32*1024*1024))
+	part := disk + "1"
+	sudo("mkfs.ext4", part)
+	err := os.MkdirAll(TMP_MOUNT_DIR, 0700)
+	if err == nil {
+		defer os.Remove(TMP_MOUNT_DIR)
+	} else {
+		t.Fatal(err)
+	}
+	sudo("mount", part, TMP_MOUNT_DIR)
+	defer sudo("umount", part)
+
+	sudo("sh", "-c", "echo 2 > "+scsiDebug+"/virtual_gb")
+	if size := getDiskSize(disk); size != GB {
+		t.Fatal("Kernel see new size without rescan:", size)
+	}
+	call(TMP_MOUNT_DIR, "--rescan", "--do")
+	if size := getDiskSize(disk); size != 2*GB {
+		t.Error("Disk doesn't rescanned:", size)
+	}
+	if blocks := df(TMP_MOUNT_DIR); blocks != 2 {
+		t.Error(blocks)
+	}
+}
+
+func TestLVMGuestExistedMappings(t *testing.T) {
+	disk, err := createTmpDevice("msdos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteTmpDevice(disk)
+
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "primary", s(MSDOS_START_BYTE), s(MSDOS_LAST_BYTE))
+
+	part := disk + "p1"
+	sudo("pvcreate", part)
+	defer sudo("pvremove", part)
+	sudo("vgcreate", LVM_VG_NAME, part)
+	defer sudo("vgremove", "-f", LVM_VG_NAME)
+	sudo("lvcreate", "-L", "500M", "-n", LVM_LV_NAME, LVM_VG_NAME)
+	lvmLV := filepath.Join("/dev", LVM_VG_NAME, LVM_LV_NAME)
+	defer sudo("lvremove", "-f", lvmLV)
+
+	// Guest disk with two partitions, mapped before start (kpartx -a)
+	// Диск гостя с двумя разделами, отображенными до запуска (kpartx -a)
+	sudo("parted", "-s", lvmLV, "mklabel", "gpt")
+	sudo("parted", "-s", lvmLV, "unit", "MiB", "mkpart", "primary", "1", "200")
+	sudo("parted", "-s", lvmLV, "unit", "MiB", "mkpart", "primary", "200", "400")
+	if err = kpartxUpdate(lvmLV); err != nil {
+		t.Fatal(err)
+	}
+	major, minor := getMajorMinor(lvmLV)
+	existed := kpartxMappings(major, minor)
+	defer func() {
+		for _, mapping := range kpartxMappings(major, minor) {
+			sudo("dmsetup", "remove", mapping.Name)
+		}
+	}()
+	if len(existed) != 2 {
+		t.Fatal("Partitions doesn't mapped:", existed)
+	}
+	lastPart := "/dev/mapper/" + existed[2].Name
+	sudo("mkfs.ext4", lastPart)
+	oldSize := getDiskSize(lastPart)
+
+	call(lvmLV, "--guest", "--do")
+
+	newSize := getDiskSize(lastPart)
+	if newSize <= oldSize {
+		t.Error("Guest partition doesn't extend:", oldSize, newSize)
+	}
+	if size, err := fsGetSizeExt(lastPart); err != nil || size <= oldSize {
+		t.Error("Filesystem of guest doesn't extend:", size, err)
+	}
+	// Existed mappings stay after work
+	// Существовавшие отображения остаются после работы
+	if res := kpartxMappings(major, minor); len(res) != 2 || res[1].Name != existed[1].Name || res[2].Name != existed[2].Name {
+		t.Error("Existed mappings changed:", existed, res)
+	}
+}
+
+// Backing file of loop device
+func loopFile(path string) string {
+	res, _, _ := sudo("losetup", path)
+	start := strings.Index(res, "(")
+	finish := strings.Index(res, ")")
+	if start == -1 || finish < start {
+		return ""
+	}
+	return res[start+1 : finish]
+}
+
+func TestExt4GuestLoopCleanup(t *testing.T) {
+	disk, err := createTmpDeviceSize("gpt", GB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteTmpDevice(disk)
+
+	sudo("parted", "-s", disk, "unit", "MiB", "mkpart", "primary", "1", "500")
+	sudo("mkfs.ext4", disk+"p1")
+	oldSize, err := fsGetSizeExt(disk + "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Guest disk without kernel partitions: they are created by --guest and removed after work
+	// Диск гостя без разделов ядра: их создает --guest и удаляет после работы
+	sudo("partx", "-d", disk)
+	sudo("udevadm", "settle")
+
+	call(disk, "--guest", "--do")
+
+	if _, err = os.Stat(disk + "p1"); err == nil {
+		t.Error("Partitions of guest disk doesn't removed")
+	}
+	sudo("partx", "-a", disk)
+	defer sudo("partx", "-d", disk)
+	sudo("udevadm", "settle")
+	if size, err := fsGetSizeExt(disk + "p1"); err != nil || size <= oldSize {
+		t.Error("Filesystem of guest doesn't extend:", oldSize, size, err)
+	}
+}
+
+func TestLVMImageSameVGName(t *testing.T) {
+	// Image with VG, which has same name as VG of host
+	// Образ с группой томов, имя которой совпадает с группой томов хоста
+	image, err := createTmpDeviceSize("gpt", GB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imageFile := loopFile(image)
+	defer os.Remove(imageFile)
+	sudo("parted", "-s", image, "unit", "MiB", "mkpart", "primary", "1", "500")
+	sudo("pvcreate", image+"p1")
+	sudo("vgcreate", LVM_VG_NAME, image+"p1")
+	sudo("lvcreate", "-L", "200M", "-n", LVM_LV_NAME, LVM_VG_NAME)
+	sudo("mkfs.ext4", filepath.Join("/dev", LVM_VG_NAME, LVM_LV_NAME))
+	sudo("vgchange", "-an", LVM_VG_NAME)
+	sudo("losetup", "-d", image)
+	if err = os.Truncate(imageFile, 2*GB); err != nil {
+		t.Fatal(err)
+	}
+
+	disk, err := createTmpDevice("msdos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteTmpDevice(disk)
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "primary", s(MSDOS_START_BYTE), s(MSDOS_START_BYTE+GB))
+	part := disk + "p1"
+	sudo("pvcreate", part)
+	defer sudo("pvremove", part)
+	sudo("vgcreate", LVM_VG_NAME, part)
+	defer sudo("vgremove", "-f", LVM_VG_NAME)
+
+	call("image", imageFile, "--lv="+LVM_VG_NAME+"/"+LVM_LV_NAME, "--do")
+
+	if res, _, _ := sudo("losetup", "-j", imageFile); strings.TrimSpace(res) != "" {
+		t.Error("Image doesn't detached:", res)
+	}
+	if res, _, _ := sudo("vgs", "--noheadings", "-o", "vg_name"); strings.Contains(res, LVM_VG_NAME+"_loop") {
+		t.Error("Temporary VG of image stays on host:", res)
+	}
+
+	// VG of image has own name, LV grows
+	// Группа томов образа имеет свое имя, LV вырос
+	res, _, _ := sudo("losetup", "--find", "--show", "--partscan", imageFile)
+	loop := strings.TrimSpace(res)
+	defer sudo("losetup", "-d", loop)
+	sudo("udevadm", "settle")
+	config := lvmImageConfig(loop)
+	if res, _, _ = sudo("pvs", "--config", config, "--noheadings", "-o", "vg_name"); strings.TrimSpace(res) != LVM_VG_NAME {
+		t.Error("VG name of image doesn't restored:", res)
+	}
+	res, _, _ = sudo("lvs", "--config", config, "--noheadings", "--units", "b", "--nosuffix", "-o", "lv_size")
+	if size, _ := parseUint(strings.TrimSpace(res)); size <= 200*1024*1024 {
+		t.Error("LV of image doesn't extend:", res)
+	}
+}
@@ -0,0 +1,218 @@
+package fsextender
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Line of /proc/self/mountinfo.
+// Строка /proc/self/mountinfo.
+type mountInfo struct {
+	ID           int
+	ParentID     int
+	Major        int
+	Minor        int
+	Root         string // Path in filesystem, which mounted. "/" - whole filesystem. Путь внутри файловой системы, который смонтирован
+	MountPoint   string
+	Options      string // Per-mount options: rw,noatime. Опции точки монтирования
+	FSType       string
+	Source       string
+	SuperOptions string
+}
+
+var errMountInfoLine = errors.New("Bad line of mountinfo")
+
+func readMountInfo() ([]mountInfo, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseMountInfo(f)
+}
+
+/*
+Parse mountinfo, format:
+36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+Count of optional fields (master:1) is variable, they end with "-".
+
+Разбирает mountinfo. Количество необязательных полей (master:1) разное, они заканчиваются "-".
+*/
+func parseMountInfo(r io.Reader) (res []mountInfo, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator == -1 || len(fields) < separator+3 {
+			return nil, fmt.Errorf("%v: %v", errMountInfoLine, scanner.Text())
+		}
+		var mount mountInfo
+		mount.ID, err = strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", errMountInfoLine, scanner.Text())
+		}
+		mount.ParentID, err = strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", errMountInfoLine, scanner.Text())
+		}
+		if _, err = fmt.Sscanf(fields[2], "%d:%d", &mount.Major, &mount.Minor); err != nil {
+			return nil, fmt.Errorf("%v: %v", errMountInfoLine, scanner.Text())
+		}
+		mount.Root = unescapeMountField(fields[3])
+		mount.MountPoint = unescapeMountField(fields[4])
+		mount.Options = fields[5]
+		mount.FSType = fields[separator+1]
+		mount.Source = unescapeMountField(fields[separator+2])
+		if len(fields) > separator+3 {
+			mount.SuperOptions = unescapeMountField(fields[separator+3])
+		}
+		res = append(res, mount)
+	}
+	return res, scanner.Err()
+}
+
+// Kernel escapes space, tab, newline and backslash as octal: \040
+// Ядро заменяет пробел, табуляцию, перевод строки и обратную косую черту восьмеричными кодами: \040
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	res := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if code, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				res = append(res, byte(code))
+				i += 3
+				continue
+			}
+		}
+		res = append(res, s[i])
+	}
+	return string(res)
+}
+
+// Path is mountPoint or inside it.
+// Путь совпадает с mountPoint или находится внутри нее.
+func pathInside(path, mountPoint string) bool {
+	return path == mountPoint || mountPoint == "/" || strings.HasPrefix(path, mountPoint+"/")
+}
+
+/*
+Find mount, which contains path. It is mount with longest mount point. If some mounts have same mount point (overmount)
+- top of them, which mounted over other.
+
+Находит точку монтирования, в которой находится путь. Это точка с самым длинным путем. Если несколько точек
+монтирования имеют один путь (перекрытие) - верхняя из них, смонтированная поверх других.
+*/
+func findMount(mounts []mountInfo, path string) (mount mountInfo, ok bool) {
+	best := -1
+	for i, m := range mounts {
+		if !pathInside(path, m.MountPoint) {
+			continue
+		}
+		switch {
+		case best == -1 || len(m.MountPoint) > len(mounts[best].MountPoint):
+			best = i
+		case m.MountPoint == mounts[best].MountPoint && m.ParentID == mounts[best].ID:
+			best = i
+		}
+	}
+	if best == -1 {
+		return mount, false
+	}
+	return mounts[best], true
+}
+
+// Value of option from comma separated list: upperdir=/path
+// Значение параметра из списка через запятую: upperdir=/path
+func mountOption(options, name string) string {
+	for _, option := range strings.Split(options, ",") {
+		if strings.HasPrefix(option, name+"=") {
+			return option[len(name)+1:]
+		}
+	}
+	return ""
+}
+
+/*
+Return block device of mount. Bind mount has device of original filesystem. Btrfs (subvolume too) has anonymous device
+number, so device is source of mount. For overlayfs it is device of upper directory.
+
+Возвращает блочное устройство точки монтирования. У bind-монтирования устройство исходной файловой системы. У btrfs
+(и ее подтомов) анонимный номер устройства, поэтому устройство - источник монтирования. Для overlayfs это устройство
+верхней папки.
+*/
+func mountDevice(mounts []mountInfo, mount mountInfo) (device string, err error) {
+	for deep := 0; deep < max_STORAGE_DEEP; deep++ {
+		switch {
+		case mount.FSType == "overlay":
+			upper := mountOption(mount.SuperOptions, "upperdir")
+			if upper == "" {
+				return "", fmt.Errorf("Overlayfs without upperdir: %v", mount.MountPoint)
+			}
+			var ok bool
+			if mount, ok = findMount(mounts, upper); !ok {
+				return "", fmt.Errorf("Can't find mount of upperdir: %v", upper)
+			}
+			continue
+		case mount.Major != 0:
+			// Keep name from mount source (/dev/mapper/vg-lv) if it is same device
+			// Оставляем имя из источника монтирования (/dev/mapper/vg-lv), если это то же устройство
+			if filepath.IsAbs(mount.Source) {
+				if major, minor := getMajorMinor(mount.Source); major == mount.Major && minor == mount.Minor {
+					return mount.Source, nil
+				}
+			}
+			if device = deviceByMajorMinor(mount.Major, mount.Minor); device != "" {
+				return device, nil
+			}
+			return "", fmt.Errorf("Can't find device %v:%v of mount %v", mount.Major, mount.Minor, mount.MountPoint)
+		case filepath.IsAbs(mount.Source):
+			return mount.Source, nil
+		default:
+			return "", fmt.Errorf("Mount %v (%v) hasn't block device", mount.MountPoint, mount.FSType)
+		}
+	}
+	return "", fmt.Errorf("Too deep overlayfs: %v", mount.MountPoint)
+}
+
+/*
+Resolve any path to mount of its filesystem and block device of the filesystem.
+
+Находит для любого пути точку монтирования его файловой системы и блочное устройство этой файловой системы.
+*/
+func resolvePath(path string) (mount mountInfo, device string, err error) {
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return mount, "", err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return mount, "", err
+	}
+	mounts, err := readMountInfo()
+	if err != nil {
+		return mount, "", err
+	}
+	mount, ok := findMount(mounts, path)
+	if !ok {
+		return mount, "", fmt.Errorf("Can't find mount of path: %v", path)
+	}
+	device, err = mountDevice(mounts, mount)
+	return mount, device, err
+}
@@ -0,0 +1,209 @@
+package fsextender
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rekby/fsextender/probe"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// Version of plan file format. Types of items are saved as numbers, so new type change version.
+// Версия формата файла плана. Типы элементов сохраняются числами, поэтому новый тип меняет версию.
+const planFile_VERSION = 4
+
+/*
+Saved plan: plans of targets and fingerprints of devices, which the plans touch. Plan can be applied only if devices
+didn't change after save.
+
+Сохраненный план: планы целей и отпечатки устройств, которые они затрагивают. План можно применить, только если
+устройства не изменились после сохранения.
+*/
+type planFile struct {
+	Version      int
+	Plans        []savedPlan
+	Fingerprints []deviceFingerprint
+}
+
+type savedPlan struct {
+	Target string // Start point of plan. Точка старта плана
+	Plan   []storageItem
+}
+
+/*
+State of device, which must stay same between save and apply of plan. For partitions it is state of disk: partition
+table and extents of all partitions.
+
+Состояние устройства, которое должно остаться неизменным между сохранением и применением плана. Для разделов это
+состояние диска: таблица разделов и границы всех разделов.
+*/
+type deviceFingerprint struct {
+	Type       storageItemType
+	Path       string
+	Size       uint64            `json:",omitempty"`
+	PartTable  string            `json:",omitempty"`
+	DiskID     string            `json:",omitempty"` // GPT disk GUID or msdos disk signature. GUID диска GPT или сигнатура msdos
+	Partitions []partitionExtent `json:",omitempty"`
+	UUID       string            `json:",omitempty"` // UUID of filesystem, swap, PV, VG or LV. UUID файловой системы, swap, PV, VG или LV
+	VGUUID     string            `json:",omitempty"` // UUID of VG of PV. UUID группы томов PV
+	File       string            `json:",omitempty"` // Backing file of loop device. Файл loop-устройства
+	Table      string            `json:",omitempty"` // Table of device mapper device. Таблица устройства device mapper
+}
+
+type partitionExtent struct {
+	Number    uint32
+	FirstByte uint64
+	LastByte  uint64
+}
+
+// Save plans with fingerprints of current state of their devices.
+// Сохраняет планы с отпечатками текущего состояния их устройств.
+func savePlan(path string, plans []savedPlan) error {
+	return writePlanFile(path, planFile{Version: planFile_VERSION, Plans: plans, Fingerprints: planFingerprints(plans)})
+}
+
+func writePlanFile(path string, file planFile) error {
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+func loadPlan(path string) (file planFile, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+	if err = json.Unmarshal(content, &file); err != nil {
+		return file, err
+	}
+	if file.Version != planFile_VERSION {
+		return file, fmt.Errorf("Unsupported version of plan file: %v", file.Version)
+	}
+	return file, nil
+}
+
+// Read fingerprints of all devices, which plans touch. Every device read once.
+// Читает отпечатки всех устройств, которые затрагивают планы. Каждое устройство читается один раз.
+func planFingerprints(plans []savedPlan) (res []deviceFingerprint) {
+	found := make(map[string]bool)
+	for _, saved := range plans {
+		for _, item := range saved.Plan {
+			fpType, path, ok := fingerprintDevice(item)
+			if !ok || found[fpType.String()+path] {
+				continue
+			}
+			found[fpType.String()+path] = true
+			res = append(res, readFingerprint(fpType, path))
+		}
+	}
+	return res
+}
+
+// Return device, which fingerprint protect the item. ok == false if the item doesn't need fingerprint.
+// Возвращает устройство, отпечаток которого защищает элемент. ok == false, если элементу отпечаток не нужен.
+func fingerprintDevice(item storageItem) (fpType storageItemType, path string, ok bool) {
+	switch item.Type {
+	case type_SKIP, type_UNKNOWN:
+		return type_UNKNOWN, "", false
+	case type_PARTITION, type_PARTITION_NEW:
+		if item.Partition.Disk == nil {
+			return type_UNKNOWN, "", false
+		}
+		return type_DISK, item.Partition.Disk.Path, true
+	case type_LVM_PV_NEW:
+		// Doesn't exist yet, disk protected by partition
+		// Еще не существует, диск защищен отпечатком раздела
+		return type_UNKNOWN, "", false
+	case type_LVM_PV_ADD:
+		return type_LVM_PV, item.Path, true
+	default:
+		return item.Type, item.Path, true
+	}
+}
+
+// Read current state of device.
+// Читает текущее состояние устройства.
+func readFingerprint(fpType storageItemType, path string) (fp deviceFingerprint) {
+	fp.Type = fpType
+	fp.Path = path
+	switch fpType {
+	case type_DISK:
+		disk, err := readDiskInfo(path)
+		if err != nil {
+			return fp
+		}
+		fp.Size, fp.PartTable, fp.DiskID = disk.Size, disk.PartTable, disk.DiskID
+		for _, part := range disk.Partitions {
+			if part.IsFreeSpace() {
+				continue
+			}
+			fp.Partitions = append(fp.Partitions, partitionExtent{Number: part.Number, FirstByte: part.FirstByte,
+				LastByte: part.LastByte})
+		}
+	case type_LVM_PV:
+		fp.Size = getDiskSize(path)
+		if pv, ok := lvmState.Get().PV(path); ok {
+			fp.UUID, fp.VGUUID = pv.UUID, pv.VGUUID
+		}
+	case type_LVM_GROUP:
+		if vg, ok := lvmState.Get().VG(path); ok {
+			fp.UUID, fp.Size = vg.UUID, uint64(vg.Size)
+		}
+	case type_LVM_LV, type_LVM_THIN_POOL:
+		if lv, ok := lvmState.Get().LV(path); ok {
+			fp.UUID, fp.Size = lv.UUID, uint64(lv.Size)
+		}
+	case type_LOOP:
+		fp.Size = getDiskSize(path)
+		major, minor := getMajorMinor(path)
+		if loop, ok := readLoopInfo(major, minor); ok {
+			fp.File = loop.File
+		}
+	case type_DM:
+		fp.Size = getDiskSize(path)
+		major, minor := getMajorMinor(path)
+		if dm, ok := readDMInfo(major, minor); ok {
+			if table, err := readDMTable(dm.Name); err == nil {
+				fp.Table = formatDMTable(table)
+			}
+		}
+	case type_FS, type_SWAP:
+		fp.Size = getDiskSize(path)
+		if res, err := probe.ProbeFile(path); err == nil {
+			fp.UUID = res.UUID
+		}
+	default:
+		fp.Size = getDiskSize(path)
+	}
+	return fp
+}
+
+// Check if devices of plan file doesn't change after save.
+// Проверяет, что устройства из файла плана не изменились после сохранения.
+func checkPlanFingerprints(file planFile) error {
+	current := make([]deviceFingerprint, len(file.Fingerprints))
+	for i, fp := range file.Fingerprints {
+		current[i] = readFingerprint(fp.Type, fp.Path)
+	}
+	return compareFingerprints(file.Fingerprints, current)
+}
+
+func compareFingerprints(saved, current []deviceFingerprint) error {
+	if len(saved) != len(current) {
+		return fmt.Errorf("Different count of fingerprints: %v != %v", len(saved), len(current))
+	}
+	var changed []string
+	for i := range saved {
+		if !reflect.DeepEqual(saved[i], current[i]) {
+			changed = append(changed, fmt.Sprintf("%v (%v):\nsaved:   %+v\ncurrent: %+v", saved[i].Path, saved[i].Type,
+				saved[i], current[i]))
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("Devices changed after save of plan:\n%v", strings.Join(changed, "\n"))
+	}
+	return nil
+}
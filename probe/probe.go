@@ -0,0 +1,455 @@
+/*
+Package probe detects content of block device by on-disk signatures and reads sizes from superblocks, without external
+tools. Type names are same as in blkid.
+
+Пакет probe определяет содержимое блочного устройства по сигнатурам на диске и читает размеры из суперблоков, без
+внешних утилит. Имена типов такие же, как в blkid.
+*/
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// Types of content, as in blkid.
+// Типы содержимого, как в blkid.
+const (
+	TYPE_EXT2  = "ext2"
+	TYPE_EXT3  = "ext3"
+	TYPE_EXT4  = "ext4"
+	TYPE_XFS   = "xfs"
+	TYPE_BTRFS = "btrfs"
+	TYPE_LVM2  = "LVM2_member"
+	TYPE_LUKS  = "crypto_LUKS"
+	TYPE_SWAP  = "swap"
+	TYPE_MD    = "linux_raid_member"
+	TYPE_VFAT  = "vfat"
+	TYPE_F2FS  = "f2fs"
+	TYPE_NTFS  = "ntfs"
+)
+
+var ErrUnknown = errors.New("probe: unknown content")
+
+type Result struct {
+	Type       string
+	UUID       string
+	Label      string
+	Size       uint64   // Size of filesystem or data area in bytes. 0 - unknown. Размер файловой системы или области данных. 0 - неизвестен
+	BlockSize  uint64   // Block size of filesystem. Размер блока файловой системы
+	Features   []string // Features of ext2/3/4, as in tune2fs. Опции ext2/3/4, как в tune2fs
+	SectorSize uint64   // Sector size of vfat and NTFS. Размер сектора vfat и NTFS
+
+	// Areas of LVM2 PV from PV header. First data area starts at pe_start.
+	// Области LVM2 PV из заголовка PV. Первая область данных начинается с pe_start.
+	LVMDataAreas     []Area
+	LVMMetadataAreas []Area
+}
+
+// Area of device in bytes. Size 0 - up to end of device.
+// Область устройства в байтах. Size 0 - до конца устройства.
+type Area struct {
+	Offset uint64
+	Size   uint64
+}
+
+type prober func(r io.ReaderAt, size uint64) (Result, bool)
+
+// Order is important: raid and containers first, they can contain signatures of content.
+// Порядок важен: raid и контейнеры первыми, они могут содержать сигнатуры содержимого.
+var probers = []prober{probeMD, probeLUKS, probeLVM2, probeSwap, probeXFS, probeExt, probeBtrfs, probeF2FS, probeNTFS,
+	probeVFAT}
+
+// Detect content of device. size - size of device, need for signatures at end of device.
+// Определяет содержимое устройства. size - размер устройства, нужен для сигнатур в конце устройства.
+func Probe(r io.ReaderAt, size uint64) (Result, error) {
+	for _, p := range probers {
+		if res, ok := p(r, size); ok {
+			return res, nil
+		}
+	}
+	return Result{}, ErrUnknown
+}
+
+// Detect content of device or image file.
+// Определяет содержимое устройства или файла образа.
+func ProbeFile(path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	// Seek to end return size of block device too
+	// Переход в конец возвращает размер и для блочного устройства
+	size, err := f.Seek(0, 2)
+	if err != nil {
+		return Result{}, err
+	}
+	return Probe(f, uint64(size))
+}
+
+func read(r io.ReaderAt, offset int64, length int) []byte {
+	buf := make([]byte, length)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return nil
+	}
+	return buf
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func cString(b []byte) string {
+	if end := bytes.IndexByte(b, 0); end != -1 {
+		b = b[:end]
+	}
+	return strings.TrimSpace(string(b))
+}
+
+var le = binary.LittleEndian
+var be = binary.BigEndian
+
+/////////////////////////// ext2/3/4 ///////////////////////////
+
+type extFeature struct {
+	field int // offset of feature field in superblock
+	mask  uint32
+	name  string
+}
+
+const (
+	ext_COMPAT    = 0x5c
+	ext_INCOMPAT  = 0x60
+	ext_RO_COMPAT = 0x64
+)
+
+var extFeatures = []extFeature{
+	{ext_COMPAT, 0x1, "dir_prealloc"},
+	{ext_COMPAT, 0x4, "has_journal"},
+	{ext_COMPAT, 0x8, "ext_attr"},
+	{ext_COMPAT, 0x10, "resize_inode"},
+	{ext_COMPAT, 0x20, "dir_index"},
+	{ext_COMPAT, 0x200, "sparse_super2"},
+	{ext_INCOMPAT, 0x2, "filetype"},
+	{ext_INCOMPAT, 0x4, "needs_recovery"},
+	{ext_INCOMPAT, 0x8, "journal_dev"},
+	{ext_INCOMPAT, 0x10, "meta_bg"},
+	{ext_INCOMPAT, 0x40, "extent"},
+	{ext_INCOMPAT, 0x80, "64bit"},
+	{ext_INCOMPAT, 0x100, "mmp"},
+	{ext_INCOMPAT, 0x200, "flex_bg"},
+	{ext_INCOMPAT, 0x1000, "large_dir"},
+	{ext_INCOMPAT, 0x8000, "inline_data"},
+	{ext_INCOMPAT, 0x10000, "encrypt"},
+	{ext_RO_COMPAT, 0x1, "sparse_super"},
+	{ext_RO_COMPAT, 0x2, "large_file"},
+	{ext_RO_COMPAT, 0x8, "huge_file"},
+	{ext_RO_COMPAT, 0x10, "uninit_bg"},
+	{ext_RO_COMPAT, 0x20, "dir_nlink"},
+	{ext_RO_COMPAT, 0x40, "extra_isize"},
+	{ext_RO_COMPAT, 0x100, "quota"},
+	{ext_RO_COMPAT, 0x200, "bigalloc"},
+	{ext_RO_COMPAT, 0x400, "metadata_csum"},
+	{ext_RO_COMPAT, 0x1000, "read-only"},
+	{ext_RO_COMPAT, 0x2000, "project"},
+}
+
+// Features, which ext3 can have. Filesystem with other features is ext4.
+// Опции, которые может иметь ext3. Файловая система с другими опциями - ext4.
+const (
+	ext3_INCOMPAT  = 0x2 | 0x4 | 0x10
+	ext3_RO_COMPAT = 0x1 | 0x2 | 0x4
+)
+
+func probeExt(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	sb := read(r, 1024, 1024)
+	if sb == nil || le.Uint16(sb[0x38:]) != 0xEF53 {
+		return res, false
+	}
+	compat, incompat, roCompat := le.Uint32(sb[ext_COMPAT:]), le.Uint32(sb[ext_INCOMPAT:]), le.Uint32(sb[ext_RO_COMPAT:])
+	if incompat&0x8 != 0 {
+		// External journal device
+		// Внешний журнал
+		return res, false
+	}
+	switch {
+	case incompat&^ext3_INCOMPAT != 0 || roCompat&^ext3_RO_COMPAT != 0:
+		res.Type = TYPE_EXT4
+	case compat&0x4 != 0:
+		res.Type = TYPE_EXT3
+	default:
+		res.Type = TYPE_EXT2
+	}
+	for _, feature := range extFeatures {
+		if le.Uint32(sb[feature.field:])&feature.mask != 0 {
+			res.Features = append(res.Features, feature.name)
+		}
+	}
+
+	res.BlockSize = 1024 << le.Uint32(sb[0x18:])
+	blocks := uint64(le.Uint32(sb[0x04:]))
+	if incompat&0x80 != 0 {
+		blocks |= uint64(le.Uint32(sb[0x150:])) << 32
+	}
+	res.Size = blocks * res.BlockSize
+	res.UUID = formatUUID(sb[0x68:0x78])
+	res.Label = cString(sb[0x78:0x88])
+	return res, true
+}
+
+/////////////////////////// xfs ///////////////////////////
+
+func probeXFS(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	sb := read(r, 0, 512)
+	if sb == nil || string(sb[0:4]) != "XFSB" {
+		return res, false
+	}
+	res.Type = TYPE_XFS
+	res.BlockSize = uint64(be.Uint32(sb[4:]))
+	res.Size = be.Uint64(sb[8:]) * res.BlockSize
+	res.UUID = formatUUID(sb[32:48])
+	res.Label = cString(sb[108:120])
+	return res, true
+}
+
+/////////////////////////// btrfs ///////////////////////////
+
+func probeBtrfs(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	sb := read(r, 64*1024, 4096)
+	if sb == nil || string(sb[64:72]) != "_BHRfS_M" {
+		return res, false
+	}
+	res.Type = TYPE_BTRFS
+	res.UUID = formatUUID(sb[32:48])
+	res.Size = le.Uint64(sb[112:])
+	res.BlockSize = uint64(le.Uint32(sb[144:]))
+	res.Label = cString(sb[0x12b : 0x12b+256])
+	return res, true
+}
+
+/////////////////////////// LVM2 PV ///////////////////////////
+
+func probeLVM2(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	// Label can be in any of first 4 sectors
+	// Метка может быть в любом из первых 4 секторов
+	for sector := int64(0); sector < 4; sector++ {
+		label := read(r, sector*512, 512)
+		if label == nil || string(label[0:8]) != "LABELONE" || string(label[24:32]) != "LVM2 001" {
+			continue
+		}
+		offset := le.Uint32(label[20:])
+		if offset+40 > 512 {
+			continue
+		}
+		pvHeader := label[offset:]
+		uuid := string(pvHeader[0:32])
+		res.Type = TYPE_LVM2
+		// Format of LVM: 6-4-4-4-4-4-6
+		// Формат LVM: 6-4-4-4-4-4-6
+		res.UUID = strings.Join([]string{uuid[0:6], uuid[6:10], uuid[10:14], uuid[14:18], uuid[18:22], uuid[22:26],
+			uuid[26:32]}, "-")
+		res.Size = le.Uint64(pvHeader[32:])
+		// Lists of data and metadata areas after device size, every list ends with zero area
+		// Списки областей данных и метаданных после размера устройства, каждый список заканчивается нулевой областью
+		areas := pvHeader[40:]
+		res.LVMDataAreas, areas = readLVMAreas(areas)
+		res.LVMMetadataAreas, _ = readLVMAreas(areas)
+		return res, true
+	}
+	return res, false
+}
+
+// Read list of disk_locn until zero offset. Return the list and rest of buffer after terminator.
+// Читает список disk_locn до нулевого смещения. Возвращает список и остаток буфера после терминатора.
+func readLVMAreas(buf []byte) (res []Area, rest []byte) {
+	for len(buf) >= 16 {
+		area := Area{Offset: le.Uint64(buf[0:]), Size: le.Uint64(buf[8:])}
+		buf = buf[16:]
+		if area.Offset == 0 {
+			return res, buf
+		}
+		res = append(res, area)
+	}
+	return res, nil
+}
+
+/////////////////////////// LUKS ///////////////////////////
+
+func probeLUKS(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	hdr := read(r, 0, 512)
+	if hdr == nil || string(hdr[0:6]) != "LUKS\xba\xbe" {
+		return res, false
+	}
+	res.Type = TYPE_LUKS
+	res.UUID = cString(hdr[168:208])
+	if be.Uint16(hdr[6:]) == 2 {
+		res.Label = cString(hdr[24:72])
+	}
+	return res, true
+}
+
+/////////////////////////// swap ///////////////////////////
+
+func probeSwap(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	for _, pageSize := range []int64{4096, 8192, 16384, 65536} {
+		magic := read(r, pageSize-10, 10)
+		if magic == nil || string(magic) != "SWAPSPACE2" && string(magic) != "SWAP-SPACE" {
+			continue
+		}
+		res.Type = TYPE_SWAP
+		res.BlockSize = uint64(pageSize)
+		if string(magic) == "SWAP-SPACE" {
+			// Old format without header
+			// Старый формат без заголовка
+			return res, true
+		}
+		hdr := read(r, 1024, 44)
+		if hdr == nil {
+			return res, true
+		}
+		res.Size = (uint64(le.Uint32(hdr[4:])) + 1) * uint64(pageSize)
+		res.UUID = formatUUID(hdr[12:28])
+		res.Label = cString(hdr[28:44])
+		return res, true
+	}
+	return res, false
+}
+
+/////////////////////////// md raid ///////////////////////////
+
+const md_MAGIC = 0xa92b4efc
+
+func probeMD(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	// Superblock 1.1, 1.2 and 1.0
+	// Суперблок 1.1, 1.2 и 1.0
+	offsets := []int64{0, 4096}
+	if size >= 8192 {
+		offsets = append(offsets, int64((size-8192)&^4095))
+	}
+	for _, offset := range offsets {
+		sb := read(r, offset, 256)
+		if sb == nil || le.Uint32(sb[0:]) != md_MAGIC || le.Uint32(sb[4:]) != 1 {
+			continue
+		}
+		res.Type = TYPE_MD
+		res.UUID = formatUUID(sb[16:32])
+		res.Label = cString(sb[32:64])
+		res.Size = le.Uint64(sb[136:]) * 512
+		return res, true
+	}
+
+	// Superblock 0.90 at end of device, aligned by 64K
+	// Суперблок 0.90 в конце устройства, выровнен по 64K
+	if size >= 128*1024 {
+		sb := read(r, int64(size&^(64*1024-1)-64*1024), 64)
+		if sb != nil && le.Uint32(sb[0:]) == md_MAGIC && le.Uint32(sb[4:]) == 0 {
+			res.Type = TYPE_MD
+			uuid := make([]byte, 16)
+			copy(uuid[0:4], sb[20:24])
+			copy(uuid[4:16], sb[52:64])
+			res.UUID = formatUUID(uuid)
+			res.Size = uint64(le.Uint32(sb[32:])) * 1024
+			return res, true
+		}
+	}
+	return res, false
+}
+
+/////////////////////////// vfat ///////////////////////////
+
+func probeVFAT(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	bs := read(r, 0, 512)
+	if bs == nil || bs[510] != 0x55 || bs[511] != 0xAA {
+		return res, false
+	}
+	var idOffset, labelOffset int
+	switch {
+	case string(bs[82:90]) == "FAT32   ":
+		idOffset, labelOffset = 0x43, 0x47
+	case string(bs[54:62]) == "FAT16   " || string(bs[54:62]) == "FAT12   ":
+		idOffset, labelOffset = 0x27, 0x2b
+	default:
+		return res, false
+	}
+	sectorSize := uint64(le.Uint16(bs[11:]))
+	if sectorSize < 512 || sectorSize > 4096 || sectorSize&(sectorSize-1) != 0 || bs[13] == 0 {
+		return res, false
+	}
+	sectors := uint64(le.Uint16(bs[19:]))
+	if sectors == 0 {
+		sectors = uint64(le.Uint32(bs[32:]))
+	}
+	res.Type = TYPE_VFAT
+	res.SectorSize = sectorSize
+	res.BlockSize = sectorSize * uint64(bs[13])
+	res.Size = sectors * sectorSize
+	id := le.Uint32(bs[idOffset:])
+	res.UUID = fmt.Sprintf("%04X-%04X", id>>16, id&0xffff)
+	if label := cString(bs[labelOffset : labelOffset+11]); label != "NO NAME" {
+		res.Label = label
+	}
+	return res, true
+}
+
+/////////////////////////// f2fs ///////////////////////////
+
+const f2fs_MAGIC = 0xF2F52010
+
+func probeF2FS(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	sb := read(r, 1024, 124+512*2)
+	if sb == nil || le.Uint32(sb[0:]) != f2fs_MAGIC {
+		return res, false
+	}
+	logBlockSize := le.Uint32(sb[16:])
+	if logBlockSize < 9 || logBlockSize > 16 {
+		return res, false
+	}
+	res.Type = TYPE_F2FS
+	res.BlockSize = 1 << logBlockSize
+	res.Size = le.Uint64(sb[36:]) << logBlockSize
+	res.UUID = formatUUID(sb[108:124])
+	// Label in UTF-16
+	// Метка в UTF-16
+	chars := make([]uint16, 0, 512)
+	for i := 124; i+1 < len(sb); i += 2 {
+		c := le.Uint16(sb[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	res.Label = string(utf16.Decode(chars))
+	return res, true
+}
+
+/////////////////////////// NTFS ///////////////////////////
+
+func probeNTFS(r io.ReaderAt, size uint64) (res Result, ok bool) {
+	bs := read(r, 0, 512)
+	if bs == nil || string(bs[3:11]) != "NTFS    " {
+		return res, false
+	}
+	sectorSize := uint64(le.Uint16(bs[11:]))
+	if sectorSize < 256 || sectorSize > 4096 || sectorSize&(sectorSize-1) != 0 || bs[13] == 0 {
+		return res, false
+	}
+	// Sectors per cluster: 1..128, or negative log2 of sectors per cluster for big clusters
+	// Секторов на кластер: 1..128, или отрицательный log2 числа секторов на кластер для больших кластеров
+	clusterSize := sectorSize * uint64(bs[13])
+	if bs[13] > 0x80 {
+		clusterSize = sectorSize << uint(256-int(bs[13]))
+	}
+	res.Type = TYPE_NTFS
+	res.SectorSize = sectorSize
+	res.BlockSize = clusterSize
+	res.Size = le.Uint64(bs[0x28:]) * sectorSize
+	res.UUID = fmt.Sprintf("%016X", le.Uint64(bs[0x48:]))
+	return res, true
+}
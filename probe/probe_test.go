@@ -0,0 +1,252 @@
+package probe
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var testUUID = []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+
+const testUUIDString = "01234567-89ab-cdef-0123-456789abcdef"
+
+func probeImage(t *testing.T, image []byte) Result {
+	res, err := Probe(bytes.NewReader(image), uint64(len(image)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestProbeExt(t *testing.T) {
+	image := make([]byte, 4096)
+	sb := image[1024:]
+	le.PutUint32(sb[0x04:], 1000)
+	le.PutUint32(sb[0x18:], 2) // 4096
+	le.PutUint16(sb[0x38:], 0xEF53)
+	le.PutUint32(sb[0x150:], 1)
+	copy(sb[0x68:], testUUID)
+	copy(sb[0x78:], "root")
+
+	res := probeImage(t, image)
+	if res.Type != TYPE_EXT2 || res.Size != 1000*4096 || res.BlockSize != 4096 || res.UUID != testUUIDString ||
+		res.Label != "root" || len(res.Features) != 0 {
+		t.Error(res)
+	}
+
+	le.PutUint32(sb[ext_COMPAT:], 0x4)
+	if res = probeImage(t, image); res.Type != TYPE_EXT3 || !reflect.DeepEqual(res.Features, []string{"has_journal"}) {
+		t.Error(res)
+	}
+
+	// blocks_count_hi used with 64bit only
+	le.PutUint32(sb[ext_INCOMPAT:], 0x80|0x40)
+	res = probeImage(t, image)
+	if res.Type != TYPE_EXT4 || res.Size != (1<<32+1000)*4096 ||
+		!reflect.DeepEqual(res.Features, []string{"has_journal", "extent", "64bit"}) {
+		t.Error(res)
+	}
+
+	// External journal
+	le.PutUint32(sb[ext_INCOMPAT:], 0x8)
+	if _, err := Probe(bytes.NewReader(image), uint64(len(image))); err != ErrUnknown {
+		t.Error(err)
+	}
+}
+
+func TestProbeXFS(t *testing.T) {
+	image := make([]byte, 4096)
+	copy(image, "XFSB")
+	be.PutUint32(image[4:], 4096)
+	be.PutUint64(image[8:], 1<<33)
+	copy(image[32:], testUUID)
+	copy(image[108:], "data")
+	res := probeImage(t, image)
+	if res.Type != TYPE_XFS || res.Size != 1<<33*4096 || res.BlockSize != 4096 || res.UUID != testUUIDString ||
+		res.Label != "data" {
+		t.Error(res)
+	}
+}
+
+func TestProbeBtrfs(t *testing.T) {
+	image := make([]byte, 128*1024)
+	sb := image[64*1024:]
+	copy(sb[32:], testUUID)
+	copy(sb[64:], "_BHRfS_M")
+	le.PutUint64(sb[112:], 10<<30)
+	le.PutUint32(sb[144:], 4096)
+	copy(sb[0x12b:], "pool")
+	res := probeImage(t, image)
+	if res.Type != TYPE_BTRFS || res.Size != 10<<30 || res.BlockSize != 4096 || res.UUID != testUUIDString ||
+		res.Label != "pool" {
+		t.Error(res)
+	}
+}
+
+func TestProbeLVM2(t *testing.T) {
+	image := make([]byte, 4096)
+	label := image[512:]
+	copy(label, "LABELONE")
+	le.PutUint64(label[8:], 1)
+	le.PutUint32(label[20:], 32)
+	copy(label[24:], "LVM2 001")
+	copy(label[32:], "abcdef0123456789ABCDEFGHIJKLMNOP")
+	le.PutUint64(label[64:], 1<<30)
+	// Data area from 1M, metadata at start and at end
+	le.PutUint64(label[72:], 1<<20)
+	le.PutUint64(label[104:], 4096)
+	le.PutUint64(label[112:], 1<<20-4096)
+	le.PutUint64(label[120:], 1<<30-1<<20)
+	le.PutUint64(label[128:], 1<<20)
+	res := probeImage(t, image)
+	if res.Type != TYPE_LVM2 || res.Size != 1<<30 || res.UUID != "abcdef-0123-4567-89AB-CDEF-GHIJ-KLMNOP" ||
+		!reflect.DeepEqual(res.LVMDataAreas, []Area{{1 << 20, 0}}) ||
+		!reflect.DeepEqual(res.LVMMetadataAreas, []Area{{4096, 1<<20 - 4096}, {1<<30 - 1<<20, 1 << 20}}) {
+		t.Error(res)
+	}
+}
+
+func TestProbeLUKS(t *testing.T) {
+	image := make([]byte, 4096)
+	copy(image, "LUKS\xba\xbe")
+	be.PutUint16(image[6:], 2)
+	copy(image[24:], "secret")
+	copy(image[168:], testUUIDString)
+	res := probeImage(t, image)
+	if res.Type != TYPE_LUKS || res.UUID != testUUIDString || res.Label != "secret" {
+		t.Error(res)
+	}
+
+	// LUKS1 hasn't label
+	be.PutUint16(image[6:], 1)
+	if res = probeImage(t, image); res.Type != TYPE_LUKS || res.Label != "" {
+		t.Error(res)
+	}
+}
+
+func TestProbeSwap(t *testing.T) {
+	image := make([]byte, 8192)
+	le.PutUint32(image[1024:], 1)
+	le.PutUint32(image[1028:], 255)
+	copy(image[1036:], testUUID)
+	copy(image[1052:], "swap0")
+	copy(image[8192-10:], "SWAPSPACE2")
+	res := probeImage(t, image)
+	if res.Type != TYPE_SWAP || res.Size != 256*8192 || res.BlockSize != 8192 || res.UUID != testUUIDString ||
+		res.Label != "swap0" {
+		t.Error(res)
+	}
+}
+
+func TestProbeMD(t *testing.T) {
+	// 1.2 - 4K from start
+	image := make([]byte, 64*1024)
+	sb := image[4096:]
+	le.PutUint32(sb[0:], md_MAGIC)
+	le.PutUint32(sb[4:], 1)
+	copy(sb[16:], testUUID)
+	copy(sb[32:], "host:0")
+	le.PutUint64(sb[136:], 100)
+	// ext superblock of array content doesn't matter
+	le.PutUint16(image[1024+0x38:], 0xEF53)
+	res := probeImage(t, image)
+	if res.Type != TYPE_MD || res.Size != 100*512 || res.UUID != testUUIDString || res.Label != "host:0" {
+		t.Error(res)
+	}
+
+	// 1.0 - near end
+	image = make([]byte, 64*1024)
+	sb = image[64*1024-8192:]
+	le.PutUint32(sb[0:], md_MAGIC)
+	le.PutUint32(sb[4:], 1)
+	if res = probeImage(t, image); res.Type != TYPE_MD {
+		t.Error(res)
+	}
+
+	// 0.90 - last 64K block, aligned
+	image = make([]byte, 200*1024)
+	sb = image[128*1024:]
+	le.PutUint32(sb[0:], md_MAGIC)
+	le.PutUint32(sb[32:], 64)
+	copy(sb[20:], testUUID[0:4])
+	copy(sb[52:], testUUID[4:16])
+	if res = probeImage(t, image); res.Type != TYPE_MD || res.Size != 64*1024 || res.UUID != testUUIDString {
+		t.Error(res)
+	}
+}
+
+func TestProbeVFAT(t *testing.T) {
+	image := make([]byte, 4096)
+	le.PutUint16(image[11:], 512)
+	image[13] = 8
+	le.PutUint32(image[32:], 204800)
+	le.PutUint32(image[0x43:], 0x1234abcd)
+	copy(image[0x47:], "EFI        ")
+	copy(image[82:], "FAT32   ")
+	image[510], image[511] = 0x55, 0xAA
+	res := probeImage(t, image)
+	if res.Type != TYPE_VFAT || res.Size != 204800*512 || res.BlockSize != 4096 || res.UUID != "1234-ABCD" ||
+		res.Label != "EFI" {
+		t.Error(res)
+	}
+
+	copy(image[0x47:], "NO NAME    ")
+	if res = probeImage(t, image); res.Label != "" {
+		t.Error(res)
+	}
+}
+
+func TestProbeUnknown(t *testing.T) {
+	if _, err := Probe(bytes.NewReader(make([]byte, 256*1024)), 256*1024); err != ErrUnknown {
+		t.Error(err)
+	}
+	// Short device
+	if _, err := Probe(bytes.NewReader(make([]byte, 100)), 100); err != ErrUnknown {
+		t.Error(err)
+	}
+}
+
+func TestProbeF2FS(t *testing.T) {
+	image := make([]byte, 4096)
+	sb := image[1024:]
+	le.PutUint32(sb[0:], f2fs_MAGIC)
+	le.PutUint32(sb[16:], 12)
+	le.PutUint64(sb[36:], 262144)
+	copy(sb[108:], testUUID)
+	for i, c := range []rune("данные") {
+		le.PutUint16(sb[124+2*i:], uint16(c))
+	}
+	res := probeImage(t, image)
+	if res.Type != TYPE_F2FS || res.Size != 262144*4096 || res.BlockSize != 4096 || res.UUID != testUUIDString ||
+		res.Label != "данные" {
+		t.Error(res)
+	}
+}
+
+func TestProbeNTFS(t *testing.T) {
+	image := make([]byte, 4096)
+	copy(image[3:], "NTFS    ")
+	le.PutUint16(image[11:], 512)
+	image[13] = 8
+	le.PutUint64(image[0x28:], 2097151)
+	le.PutUint64(image[0x48:], 0x0123456789abcdef)
+	image[510], image[511] = 0x55, 0xAA
+	res := probeImage(t, image)
+	if res.Type != TYPE_NTFS || res.Size != 2097151*512 || res.BlockSize != 4096 || res.SectorSize != 512 ||
+		res.UUID != "0123456789ABCDEF" {
+		t.Error(res)
+	}
+
+	// 2M clusters: 4096 sectors per cluster, mkntfs writes -12 (0xF4)
+	image[13] = 0xF4
+	if res = probeImage(t, image); res.BlockSize != 2*1024*1024 {
+		t.Error(res)
+	}
+
+	// 2M clusters on 4K sectors: 512 sectors per cluster, -9 (0xF7)
+	le.PutUint16(image[11:], 4096)
+	image[13] = 0xF7
+	if res = probeImage(t, image); res.BlockSize != 2*1024*1024 || res.SectorSize != 4096 {
+		t.Error(res)
+	}
+}
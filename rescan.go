@@ -0,0 +1,93 @@
+package fsextender
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+/*
+Disks under storage hierarchy, which kernel can rescan for capacity change (SCSI, virtio-scsi: device/rescan in sysfs).
+virtio-blk disks aren't returned: driver updates their capacity by itself.
+
+Диски под иерархией устройств, размер которых ядро может перечитать (SCSI, virtio-scsi: device/rescan в sysfs).
+Диски virtio-blk не возвращаются: драйвер сам обновляет их размер.
+*/
+func storageRescanDisks(storage []storageItem) (res []string) {
+	found := make(map[string]bool)
+	for _, item := range storage {
+		paths := []string{item.Path}
+		if item.Partition.Disk != nil {
+			paths = append(paths, item.Partition.Disk.Path)
+		}
+		for _, path := range paths {
+			major, minor := getMajorMinor(path)
+			if major == 0 {
+				continue
+			}
+			diskDir := sysDiskDir(fmt.Sprintf("/sys/dev/block/%v:%v", major, minor))
+			if diskDir == "" || !sysCanRescan(diskDir) {
+				continue
+			}
+			if disk := sysDeviceName(diskDir); disk != "" && !found[disk] {
+				found[disk] = true
+				res = append(res, disk)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Directory of disk in sysfs for device directory. Partition is subdirectory of its disk.
+// Папка диска в sysfs для папки устройства. Раздел - подпапка своего диска.
+func sysDiskDir(sysDir string) string {
+	dir, err := filepath.EvalSymlinks(sysDir)
+	if err != nil {
+		return ""
+	}
+	if _, err = os.Stat(filepath.Join(dir, "partition")); err == nil {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+func sysCanRescan(diskDir string) bool {
+	_, err := os.Stat(filepath.Join(diskDir, "device", "rescan"))
+	return err == nil
+}
+
+/*
+Rescan capacity of disks and wait for udev. Return true if size of any disk changed.
+
+Перечитывает размер дисков и ждет udev. Возвращает true, если размер какого-либо диска изменился.
+*/
+func rescanDisks(disks []string) (changed bool) {
+	oldSizes := make([]uint64, len(disks))
+	for i, disk := range disks {
+		oldSizes[i] = getDiskSize(disk)
+		major, minor := getMajorMinor(disk)
+		diskDir := sysDiskDir(fmt.Sprintf("/sys/dev/block/%v:%v", major, minor))
+		if err := ioutil.WriteFile(filepath.Join(diskDir, "device", "rescan"), []byte("1"), 0200); err != nil {
+			log.Println("Can't rescan disk:", disk, err)
+		}
+	}
+	if len(disks) > 0 {
+		if res, stderr, err := cmd("udevadm", "settle"); err != nil {
+			log.Printf("Can't wait udev: %v\nstdout: %v\nstderr: %v\n", err, res, stderr)
+		}
+	}
+	for i, disk := range disks {
+		newSize := getDiskSize(disk)
+		if newSize != oldSizes[i] {
+			changed = true
+			log.Printf("Disk rescanned: %v %v -> %v\n", disk, formatSize(oldSizes[i]), formatSize(newSize))
+		} else {
+			log.Printf("Disk rescanned: %v %v (size doesn't changed)\n", disk, formatSize(newSize))
+		}
+	}
+	return changed
+}
@@ -1,15 +1,13 @@
 package fsextender
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"github.com/rekby/fsextender/Godeps/_workspace/src/github.com/rekby/gpt"
 	"github.com/rekby/fsextender/Godeps/_workspace/src/github.com/rekby/mbr"
-	"io/ioutil"
+	"github.com/rekby/fsextender/probe"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -25,13 +23,40 @@ type storageItemType int
 // Максимальная глубина стека устройств. В настроящий момент используется как простой определитель циклов
 const max_STORAGE_DEEP = 100
 
+// Max count of symlinks in chain, as in kernel.
+// Максимальное количество символических ссылок в цепочке, как в ядре.
+const max_SYMLINK_DEEP = 40
+
 // Minimum size of created partition. For avoid create new partition with few KB of space.
 // Минимальный размер свободного места для создания нового раздела
 const min_SIZE_NEW_PARTITION = 100 * 1024 * 1024
 
-// Count of extents for PV metadata (for calculations).
-// Количество блоков LVM PV, резервируемых под метаданные (при расчетах).
-const lvm_PV_METADATA_RESERVED = 2
+// Geometry of new PV: label and PV header take first 4KiB, after them metadata area, data aligned after metadata.
+// Геометрия нового PV: метка и заголовок PV занимают первые 4KiB, за ними область метаданных, данные выравниваются
+// после метаданных.
+const (
+	lvm_MDA_START             = 4096
+	lvm_NEW_PV_METADATA_SIZE  = 1020 * 1024
+	lvm_NEW_PV_DATA_ALIGNMENT = 1024 * 1024
+)
+
+var lvmNewPVGeometry = lvmPVGeometry{
+	PEStart: (lvm_MDA_START + lvm_NEW_PV_METADATA_SIZE + lvm_NEW_PV_DATA_ALIGNMENT - 1) / lvm_NEW_PV_DATA_ALIGNMENT *
+		lvm_NEW_PV_DATA_ALIGNMENT,
+}
+
+// Max count of blocks in ext2/3/4 filesystem without 64bit feature.
+// Максимальное количество блоков в файловой системе ext2/3/4 без опции 64bit.
+const ext_MAX_BLOCKS_32BIT = 1<<32 - 1
+
+// Max count of clusters in FAT32 (28 bit) and max count of sectors in FAT (32 bit).
+// Максимальное количество кластеров FAT32 (28 бит) и максимальное количество секторов FAT (32 бита).
+const fat32_MAX_CLUSTERS = 0x0FFFFFF5
+const fat_MAX_SECTORS = 1<<32 - 1
+
+// Max size of ext4 filesystem with 64bit feature (1 EiB).
+// Максимальный размер файловой системы ext4 с опцией 64bit (1 EiB).
+const ext_MAX_SIZE_64BIT = 1 << 60
 
 const (
 	type_UNKNOWN storageItemType = iota
@@ -51,6 +76,22 @@ const (
 	type_PARTITION
 	type_PARTITION_NEW
 
+	// LVM thin pool. Layer between volume group and thin LV.
+	// Пул тонких томов LVM. Слой между группой томов и тонким LV.
+	type_LVM_THIN_POOL
+
+	// Loop device with backing file. Extends by growth of the file.
+	// Loop-устройство с файлом. Расширяется увеличением файла.
+	type_LOOP
+
+	// Swap on partition or LV. Top of hierarchy as filesystem.
+	// Swap на разделе или LV. Вершина иерархии, как файловая система.
+	type_SWAP
+
+	// Device mapper device with linear and striped targets (dmsetup), not LVM.
+	// Устройство device mapper с целями linear и striped (dmsetup), не LVM.
+	type_DM
+
 	type_SKIP
 	type_LAST // Doesn't use in work - for tests only.
 )
@@ -64,9 +105,38 @@ type storageItem struct {
 	// or free space in LVM Volume group.
 	// Максимальный объем, который может предоставить устройство, без учета роста нижележащих устройст
 	// Например расширение PV до размера раздела или расширение раздела до размера диска, свободное место в LVM Group и т.п.
-	FSType        string    // Type of file system (for type type_FS) тип файловой системы (для типа type_FS)
-	Partition     partition // For types type_PARTITION and type_PARTITION_NEW. Описание раздела диска - для типов (type_PARTITION, type_PARTITION_NEW)
-	LVMExtentSize uint64    // Extent size for type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW. Размер экстента для типа type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW
+	FSType        string        // Type of file system (for type type_FS) тип файловой системы (для типа type_FS)
+	FSBlockSize   uint64        // Block size of file system (for type type_FS). Размер блока файловой системы (для типа type_FS)
+	FSFeatures    []string      // Features of file system (ext2/3/4 only). Опции файловой системы (только для ext2/3/4)
+	FSEnable64bit bool          // Enable 64bit feature before resize (ext4 only). Включить опцию 64bit перед расширением (только ext4)
+	FSUmount      bool          // Umount filesystem, which grows offline only, for resize. Отмонтировать для расширения файловую систему, которая растет только offline
+	Partition     partition     // For types type_PARTITION and type_PARTITION_NEW. Описание раздела диска - для типов (type_PARTITION, type_PARTITION_NEW)
+	LVMExtentSize uint64        // Extent size for type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW, type_LVM_LV. Размер экстента для типа type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW, type_LVM_LV
+	LVMPVFree     uint64        // Unallocated space of PV (for type_LVM_PV). Нераспределенное место PV (для type_LVM_PV)
+	LVMPVGeometry lvmPVGeometry // Layout of PV (for type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW). Расположение PV
+	LVMSegType    string        // Segment type of LV (for type_LVM_LV). Тип сегмента LV (для type_LVM_LV)
+	LVMStripes    uint64        // Stripes count of LV (for type_LVM_LV). Количество полос LV (для type_LVM_LV)
+	LVMStripeSize uint64        // Stripe size of LV (for type_LVM_LV). Размер полосы LV (для type_LVM_LV)
+	LVMImagePVs   [][]string    // PVs of every image of raid/mirror LV (for type_LVM_LV). PV каждого образа raid/mirror LV (для type_LVM_LV)
+	LVMPool       string        // Thin pool of thin LV (for type_LVM_LV). Пул тонкого LV (для type_LVM_LV)
+	LVMMetaSize   uint64        // Metadata size of thin pool (for type_LVM_THIN_POOL). Размер метаданных пула (для type_LVM_THIN_POOL)
+	LVMOvercommit uint64        // How many percents thin LV can exceed its pool (for type_LVM_LV). На сколько процентов тонкий LV может превышать пул (для type_LVM_LV)
+	LVMCachePool  string        // Cache pool or cache volume of cached LV (for type_LVM_LV). Пул или том кеша LV (для type_LVM_LV)
+	LVMCacheMode  string        // Cache mode of dm-cache LV (for type_LVM_LV). Режим кеширования dm-cache LV (для type_LVM_LV)
+	LVMCachePVs   []string      // PVs of cache. Origin doesn't extend to them (for type_LVM_LV). PV кеша. Исходный LV на них не расширяется (для type_LVM_LV)
+	LoopFile      string        // Backing file (for type_LOOP). Файл loop-устройства (для type_LOOP)
+	LoopAllocate  bool          // Allocate space of file instead of sparse growth (for type_LOOP). Выделять место файлу вместо разреженного роста (для type_LOOP)
+	SwapActive    bool          // Swap is turned on (for type_SWAP). Swap включен (для type_SWAP)
+	SwapPriority  int           // Priority of active swap (for type_SWAP). Приоритет активного swap (для type_SWAP)
+	DMName        string        // Name of device mapper device (for type_DM). Имя устройства device mapper (для type_DM)
+	DMTable       []dmTarget    // Table of device mapper device (for type_DM). Таблица устройства device mapper (для type_DM)
+	DMDevices     []string      // Source devices of last target (for type_DM). Исходные устройства последней цели (для type_DM)
+	DMDevFree     []uint64      // Free space of source devices after last target (for type_DM). Свободное место исходных устройств после последней цели (для type_DM)
+
+	MaxSize   uint64 // Max size, which item can be extended to. 0 - unlimited. Максимальный размер, до которого можно расширить устройство. 0 - без ограничений
+	OverLimit uint64 // Free space above MaxSize, which can't be used. Свободное место сверх MaxSize, которое не может быть использовано
+
+	PolicyLimit uint64 // Max size by policy (target size, VG reserve). 0 - unlimited. Максимальный размер по правилам (целевой размер, резерв VG). 0 - без ограничений
 
 	SkipReason string
 	OldType    storageItemType // Type of item before skip
@@ -82,21 +152,49 @@ func (this storageItem) String() string {
 		base += ", PartNum=" + strconv.FormatUint(uint64(this.Partition.Number), 10)
 	case type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
 		base += ", ExtentSize: " + formatSize(this.LVMExtentSize)
+	case type_LVM_LV:
+		if lvmIsRaid(this.LVMSegType) {
+			base += ", " + this.LVMSegType + " images: " + strconv.Itoa(len(this.LVMImagePVs))
+		} else if this.LVMStripes > 1 {
+			base += ", Stripes: " + formatUInt(this.LVMStripes) + " (" + formatSize(this.LVMStripeSize) + ")"
+		} else if this.LVMPool != "" {
+			base += ", Pool: " + this.LVMPool
+		} else if this.LVMCachePool != "" {
+			base += ", " + this.LVMSegType + ": " + this.LVMCachePool
+		}
+	case type_LVM_THIN_POOL:
+		base += ", Metadata: " + formatSize(this.LVMMetaSize)
+	case type_LOOP:
+		base += ", File: " + this.LoopFile
+	case type_SWAP:
+		if this.SwapActive {
+			base += ", Active"
+		}
+	case type_DM:
+		if len(this.DMTable) > 0 {
+			last := this.DMTable[len(this.DMTable)-1]
+			base += ", Name: " + this.DMName + ", Targets: " + strconv.Itoa(len(this.DMTable)) + ", Last: " + last.Type
+		}
 	case type_SKIP:
 		base += ", Reason: " + this.SkipReason
 	}
+	if this.OverLimit > 0 {
+		base += ", Over max size: " + formatSize(this.OverLimit)
+	}
 	return base + "]"
 }
 
 type diskInfo struct {
-	Path              string
-	PartTable         string // msdos/gpt
-	Size              uint64 // Bytes
-	Major             int
-	Minor             int
-	SectorSizeLogical uint64 // Logical size of sector - for operation with partition table (in bytes). Логический размер сектора диска, в байтах
-	Partitions        []partition
-	MaxPartitionCount uint32
+	Path               string
+	PartTable          string // msdos/gpt
+	Size               uint64 // Bytes
+	Major              int
+	Minor              int
+	SectorSizeLogical  uint64      // Logical size of sector - for operation with partition table (in bytes). Логический размер сектора диска, в байтах
+	SectorSizePhysical uint64      // Physical size of sector (in bytes). Физический размер сектора диска, в байтах
+	DiskID             string      // GPT disk GUID or msdos disk signature. GUID диска GPT или сигнатура диска msdos
+	Partitions         []partition `json:"-"` // Partitions refer to the disk. Разделы ссылаются на диск
+	MaxPartitionCount  uint32
 }
 
 type partition struct {
@@ -105,6 +203,8 @@ type partition struct {
 	Number    uint32 // Partition numbers start start from 1. Value 0 mean free space
 	FirstByte uint64
 	LastByte  uint64
+	UUID      string // GPT partition GUID or msdos disk signature with number: 1234abcd-01. GUID раздела GPT или сигнатура диска msdos с номером
+	Label     string // Name of GPT partition. Имя раздела GPT
 }
 type partitionSortByFirstByte []partition
 
@@ -128,6 +228,11 @@ func (p partition) IsFreeSpace() bool {
 	return p.Number == 0
 }
 func (p partition) makePath() string {
+	// Partitions of device mapper disk are mapped by kpartx
+	// Разделы диска device mapper создаются kpartx
+	if path, ok := kpartxPartitionPath(*p.Disk, p.Number); ok {
+		return path
+	}
 	// Drive path ends with number, for example /dev/loop0
 	if len(p.Disk.Path) > 0 {
 		last := p.Disk.Path[len(p.Disk.Path)-1]
@@ -138,31 +243,44 @@ func (p partition) makePath() string {
 	return p.Disk.Path + strconv.FormatUint(uint64(p.Number), 10)
 }
 
+// Layout of LVM PV on device.
+// Расположение LVM PV на устройстве.
+type lvmPVGeometry struct {
+	PEStart uint64 // Offset of first extent. Смещение первого экстента
+	MDAEnd  uint64 // Space at end of device, used by second metadata area. Место в конце устройства под вторую область метаданных
+}
+
 type lvmPV struct {
 	Path        string
 	VolumeGroup string
 	Size        uint64
+	Free        uint64 // Unallocated space. Нераспределенное место
+}
+
+// Geometry of last segment of LVM LV. The segment is extended by lvresize.
+// Геометрия последнего сегмента LVM LV. Именно он расширяется при lvresize.
+type lvmLV struct {
+	Path       string // VolumeGroup/VolumeName
+	SegType    string // linear, striped, raid1, ...
+	Stripes    uint64
+	StripeSize uint64
+	ImagePVs   [][]string // PVs of every image of raid/mirror LV. PV, на которых расположены образы raid/mirror LV
+	Pool       string     // Thin pool of thin LV (VolumeGroup/PoolName). Пул тонкого LV
+	CachePool  string     // Cache pool or cache volume of cached LV (VolumeGroup/PoolName). Пул или том кеша LV
+	CacheMode  string     // Cache mode of dm-cache LV (writethrough, writeback, ...). Режим кеширования dm-cache LV
+	MetaSize   uint64     // Metadata size of thin pool. Размер метаданных пула тонких томов
 }
 
 var majorMinorDeviceTypeCache = make(map[[2]int]storageItem)
 
-func blkid(path string) string {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("blkid", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	out := buf.Bytes()
-	start := bytes.Index(out, []byte(`TYPE="`))
-	if start == -1 {
-		return ""
-	}
-	typeBytes := out[start+len(`TYPE="`):]
-	end := bytes.Index(typeBytes, []byte(`"`))
-	if end == -1 {
+// Return type of content of device, as blkid. Empty string if content unknown.
+// Возвращает тип содержимого устройства, как blkid. Пустая строка, если содержимое неизвестно.
+func probeType(path string) string {
+	res, err := probe.ProbeFile(path)
+	if err != nil {
 		return ""
 	}
-
-	return string(typeBytes[:end])
+	return res.Type
 }
 
 var diskNewPartitionNumLastGeneratedNum = make(map[[2]int]uint32)
@@ -185,7 +303,14 @@ partNumLoop:
 	return 0
 }
 
-func extendScanWays(startPoint string) (storage []storageItem, err error) {
+/*
+Scan storage under start point. guest - start point is partition of guest disk (LV or loop device), the disk can grow
+with its last partition.
+
+Сканирует устройства под точкой старта. guest - точка старта - раздел гостевого диска (LV или loop-устройство), диск
+может расти вместе с последним разделом.
+*/
+func extendScanWays(startPoint string, guest bool) (storage []storageItem, err error) {
 	startPoint = filepath.Clean(startPoint)
 	startPoint, err = filepath.Abs(startPoint)
 	if err != nil {
@@ -197,30 +322,21 @@ func extendScanWays(startPoint string) (storage []storageItem, err error) {
 		log.Println("Can't readlink of startpoint: ", startPoint)
 		return
 	}
+	blockDevCached.Begin()
+	defer blockDevCached.End()
 	scanLVM()
 
-	// Check if startPoint is mount point of file system. If yes - find mounted device. Take last mount line.
-	// проверяем является ли startPoint точкой монтирования. Если да - находим смонтированное устройство.
-	// Если подходящих строк монтирования несколько - берём последнюю строку.
-	mountsBytes, err := ioutil.ReadFile("/proc/mounts")
-	if err != nil {
-		return
-	}
-	mountFrom := ""
-	for _, lineBytes := range bytes.Split(mountsBytes, []byte("\n")) {
-		parts := bytes.Split(lineBytes, []byte(" "))
-		if len(parts) < 3 {
-			// empty line
-			continue
-		}
-		from := parts[0]
-		to := parts[1]
-		if string(to) == startPoint {
-			mountFrom = string(from)
+	// Start point can be block device or any path. Path resolved to device of its filesystem.
+	// Точка старта может быть блочным устройством или любым путем. Путь заменяется устройством его файловой системы.
+	if stat, statErr := os.Stat(startPoint); statErr != nil || stat.Mode()&os.ModeDevice == 0 ||
+		stat.Mode()&os.ModeCharDevice != 0 {
+		var mount mountInfo
+		mount, startPoint, err = resolvePath(startPoint)
+		if err != nil {
+			log.Println("Can't find filesystem of start point:", err)
+			return
 		}
-	}
-	if mountFrom != "" {
-		startPoint = mountFrom
+		log.Printf("Filesystem of start point: %v (%v, mounted to %v)\n", startPoint, mount.FSType, mount.MountPoint)
 	}
 	startPoint, err = readLink(startPoint)
 	if err != nil {
@@ -241,12 +357,14 @@ toScanLoop:
 
 		switch item.Type {
 		case type_UNKNOWN:
-			blk := blkid(item.Path)
+			blk := probeType(item.Path)
 			major, minor := getMajorMinor(item.Path)
 			switch {
-			case blk == "ext2", blk == "ext3", blk == "ext4", blk == "xfs":
+			case blk == "ext2", blk == "ext3", blk == "ext4", blk == "xfs", fsOffline(blk):
 				item.Type = type_FS
 				item.FSType = blk
+			case blk == probe.TYPE_SWAP:
+				item.Type = type_SWAP
 			case getTypeByMajorMinor(major, minor) != type_UNKNOWN:
 				item.Type = getTypeByMajorMinor(major, minor)
 			default:
@@ -267,12 +385,26 @@ toScanLoop:
 					log.Printf("Can't get size of filesystem: %v (%v). Skip it.\n", item.Path, err)
 					continue toScanLoop
 				}
+				item.FSBlockSize, item.FSFeatures, err = fsGetFeaturesExt(item.Path)
+				if err != nil {
+					log.Printf("Can't get features of filesystem: %v (%v). Skip it.\n", item.Path, err)
+					continue toScanLoop
+				}
+				item.MaxSize = fsMaxSize(item.FSType, item.FSBlockSize, item.FSFeatures)
 			case "xfs":
-				item.Size, err = fsGetSizeXFS(item.Path)
+				item.Size, item.FSBlockSize, err = fsGetInfoXFS(item.Path)
 				if err != nil {
 					log.Printf("Can't get size of filesystem: %v (%v). Skip it.\n", item.Path, err)
 					continue toScanLoop
 				}
+			case probe.TYPE_F2FS, probe.TYPE_VFAT, probe.TYPE_NTFS:
+				res, err := probe.ProbeFile(item.Path)
+				if err != nil || res.Type != item.FSType || res.Size == 0 {
+					log.Printf("Can't get size of filesystem: %v (%v). Skip it.\n", item.Path, err)
+					continue toScanLoop
+				}
+				item.Size, item.FSBlockSize = res.Size, res.BlockSize
+				item.MaxSize = fsMaxSize(item.FSType, item.FSBlockSize, item.FSFeatures)
 			default:
 				log.Printf("I don't khow method to detect size of filesystem %v (%v). Skip it.", item.Path, item.FSType)
 				continue toScanLoop
@@ -290,6 +422,30 @@ toScanLoop:
 				toScan = append(toScan, newItem)
 			}
 
+		case type_SWAP:
+			res, err := probe.ProbeFile(item.Path)
+			if err != nil || res.Type != probe.TYPE_SWAP || res.Size == 0 {
+				log.Printf("Can't read swap header: %v (%v). Skip it.\n", item.Path, err)
+				continue toScanLoop
+			}
+			item.Size, item.FSBlockSize = res.Size, res.BlockSize
+			item.MaxSize = swap_MAX_PAGES * res.BlockSize
+			if swap, ok := swapActive(item.Path); ok {
+				item.SwapActive, item.SwapPriority = true, swap.Priority
+			}
+
+			storage = append(storage, item)
+			major, minor := getMajorMinor(item.Path)
+			underLevelType := getTypeByMajorMinor(major, minor)
+			if underLevelType != type_UNKNOWN {
+				newItem := storageItem{
+					Path:  item.Path,
+					Type:  underLevelType,
+					Child: len(storage) - 1,
+				}
+				toScan = append(toScan, newItem)
+			}
+
 		case type_PARTITION:
 			diskPath, partNumber, err := extractPartNumber(item.Path)
 			if err != nil {
@@ -330,14 +486,94 @@ toScanLoop:
 			// LVM_PV free space detection
 			if item.Child != -1 && storage[item.Child].Type == type_LVM_PV {
 				child := &storage[item.Child]
-				newSize := lvmPVCalcSize(item.Size, child.LVMExtentSize)
+				newSize := lvmPVCalcSize(item.Size, child.LVMPVGeometry, child.LVMExtentSize)
 				if newSize > child.Size {
 					child.FreeSpace = newSize - child.Size
 				}
 			}
+
+			// Disk of guest (LV or loop device) can grow, last partition takes its growth. Only in guest and image
+			// modes: partitions of running guest can't be changed from host.
+			// Диск гостя (LV или loop-устройство) может расти, его рост получает последний раздел. Только в режимах
+			// гостя и образа: разделы запущенного гостя нельзя менять с хоста.
+			if last, ok := lastPartition(disk); guest && ok && item.Type == type_PARTITION && last.Number == partNumber {
+				parent := storageItem{Path: diskPath, Child: len(storage) - 1}
+				parent.Type = getTypeByMajorMinor(disk.Major, disk.Minor)
+				if parent.Type == type_LVM_LV || parent.Type == type_LOOP {
+					if users, err := guestDiskUsers(disk.Major, disk.Minor); err != nil || users > 0 {
+						log.Printf("Guest disk is used, it doesn't grow. Shut down the guest: %v (%v opens, %v)\n",
+							diskPath, users, err)
+					} else {
+						toScan = append(toScan, parent)
+					}
+				}
+			}
 		case type_DISK:
+			// Filesystem or PV on whole disk, without partition table
+			// Файловая система или PV на всем диске, без таблицы разделов
+			item.Size = getDiskSize(item.Path)
 			storage = append(storage, item)
-			continue
+
+			// LVM_PV free space detection
+			if item.Child != -1 && storage[item.Child].Type == type_LVM_PV {
+				child := &storage[item.Child]
+				newSize := lvmPVCalcSize(item.Size, child.LVMPVGeometry, child.LVMExtentSize)
+				if newSize > child.Size {
+					child.FreeSpace = newSize - child.Size
+				}
+			}
+		case type_LOOP:
+			item.Size = getDiskSize(item.Path)
+			major, minor := getMajorMinor(item.Path)
+			loop, ok := readLoopInfo(major, minor)
+			switch {
+			case !ok:
+				skipStorageItem(&item, "Can't read backing file of loop device.")
+			case loop.SizeLimit != 0:
+				skipStorageItem(&item, "Size of loop device is limited.")
+			case strings.HasSuffix(loop.File, " (deleted)"):
+				skipStorageItem(&item, "Backing file of loop device deleted.")
+			default:
+				item.LoopFile = loop.File
+				item.FreeSpace, err = loopFreeSpace(loop, item.Size)
+				if err != nil {
+					log.Println("Can't get free space for loop device:", item.Path, loop.File, err)
+				}
+			}
+			storage = append(storage, item)
+		case type_DM:
+			item.Size = getDiskSize(item.Path)
+			major, minor := getMajorMinor(item.Path)
+			dm, _ := readDMInfo(major, minor)
+			item.DMName = dm.Name
+			var toEnd []bool
+			item.DMTable, err = readDMTable(item.DMName)
+			if err == nil {
+				err = dmCheckTable(item.DMTable)
+			}
+			if err == nil {
+				item.DMDevices, item.DMDevFree, toEnd, err = dmDevicesFree(item.DMTable)
+			}
+			if err != nil {
+				skipStorageItem(&item, fmt.Sprintf("Can't extend device mapper device: %v.", err))
+				storage = append(storage, item)
+				continue toScanLoop
+			}
+			_, item.FreeSpace = dmGrowTable(item.DMTable, item.DMDevFree, 0)
+			storage = append(storage, item)
+
+			// Source of last target can grow, if the target reaches end of it
+			// Источник последней цели может расти, если цель доходит до его конца
+			for i, path := range item.DMDevices {
+				if !toEnd[i] {
+					continue
+				}
+				major, minor := getMajorMinor(path)
+				parent := storageItem{Path: path, Type: getTypeByMajorMinor(major, minor), Child: len(storage) - 1}
+				if parent.Type != type_UNKNOWN {
+					toScan = append(toScan, parent)
+				}
+			}
 		case type_LVM_LV:
 			// Normalize path to LVM LV
 			// Если был передан полный путь к LVM - заменяем его описанием из кеша, заполненного при сканировании LVM
@@ -345,6 +581,32 @@ toScanLoop:
 				item.Path = majorMinorDeviceTypeCache[[2]int{major, minor}].Path
 			}
 			item.Size = lvmLVGetSize(item.Path)
+			lv := lvmLVGetInfo(item.Path)
+			item.LVMSegType, item.LVMStripes, item.LVMStripeSize = lv.SegType, lv.Stripes, lv.StripeSize
+			item.LVMImagePVs = lv.ImagePVs
+			item.LVMPool = lv.Pool
+			item.LVMCachePool, item.LVMCacheMode = lv.CachePool, lv.CacheMode
+			if item.LVMCachePool != "" {
+				item.LVMCachePVs = lvmCacheGetPVs(item.LVMCachePool)
+			}
+			storage = append(storage, item)
+
+			if item.LVMPool != "" {
+				// Thin LV extends by its pool
+				// Тонкий LV расширяется за счет своего пула
+				toScan = append(toScan, storageItem{Type: type_LVM_THIN_POOL, Path: item.LVMPool, Child: len(storage) - 1})
+				continue toScanLoop
+			}
+
+			lvm_group := storageItem{
+				Type:  type_LVM_GROUP,
+				Path:  item.Path[:strings.Index(item.Path, "/")],
+				Child: len(storage) - 1,
+			}
+			toScan = append(toScan, lvm_group)
+		case type_LVM_THIN_POOL:
+			item.Size = lvmLVGetSize(item.Path)
+			item.LVMMetaSize = lvmLVGetInfo(item.Path).MetaSize
 			storage = append(storage, item)
 
 			lvm_group := storageItem{
@@ -355,6 +617,13 @@ toScanLoop:
 			toScan = append(toScan, lvm_group)
 		case type_LVM_PV, type_LVM_PV_ADD:
 			item.Size = lvmPVGetSize(item.Path)
+			item.LVMPVGeometry = lvmPVReadGeometry(item.Path)
+			if item.Type == type_LVM_PV_ADD {
+				// For free PV pvs return size of device, so use calculated usable size
+				// Для свободных pv система выдает размер устройства, так что используем расчетный размер
+				item.Size = lvmPVCalcSize(item.Size, item.LVMPVGeometry, item.LVMExtentSize)
+				item.FreeSpace = item.Size
+			}
 			storage = append(storage, item)
 
 			major, minor := getMajorMinor(item.Path)
@@ -369,6 +638,14 @@ toScanLoop:
 			item.Size, item.FreeSpace, item.LVMExtentSize = lvmVGGetSize(item.Path)
 			storage = append(storage, item)
 			lvmGroupIndex := len(storage) - 1
+			// Set extent size for LV and thin pool over the group
+			// Сохраняем размер экстента для LV и пула тонких томов над группой
+			for child := item.Child; child != -1; child = storage[child].Child {
+				if storage[child].Type != type_LVM_LV && storage[child].Type != type_LVM_THIN_POOL {
+					break
+				}
+				storage[child].LVMExtentSize = item.LVMExtentSize
+			}
 
 			// Find my and free pvs
 			for _, pv := range getLvmPV() {
@@ -376,16 +653,12 @@ toScanLoop:
 					// Can use free LVM PV
 					// Незанятые PV, можно использовать
 					parent := storageItem{Path: pv.Path, Type: type_LVM_PV_ADD, Child: len(storage) - 1, LVMExtentSize: item.LVMExtentSize}
-
-					// Calc usable PV size
-					// для свободных pv  система выдает размер равный размеру раздела, так что испольузем расчетный размер
-					parent.Size = lvmPVCalcSize(pv.Size, item.LVMExtentSize)
-					parent.FreeSpace = parent.Size
 					toScan = append(toScan, parent)
 				} else if pv.VolumeGroup == item.Path {
 					// LVM PV in the LV group
 					// PV, входящие в эту группу
-					parent := storageItem{Path: pv.Path, Size: pv.Size, Type: type_LVM_PV, Child: len(storage) - 1, LVMExtentSize: item.LVMExtentSize}
+					parent := storageItem{Path: pv.Path, Size: pv.Size, Type: type_LVM_PV, Child: len(storage) - 1, LVMExtentSize: item.LVMExtentSize,
+						LVMPVFree: pv.Free}
 					toScan = append(toScan, parent)
 				} else {
 					// nothing
@@ -394,7 +667,8 @@ toScanLoop:
 
 			// Find free space for create new partition
 			for _, part := range getNewPartitions() {
-				pvCreate := storageItem{Child: lvmGroupIndex, Path: part.Path, Type: type_LVM_PV_NEW, LVMExtentSize: item.LVMExtentSize}
+				pvCreate := storageItem{Child: lvmGroupIndex, Path: part.Path, Type: type_LVM_PV_NEW, LVMExtentSize: item.LVMExtentSize,
+					LVMPVGeometry: lvmNewPVGeometry}
 				storage = append(storage, pvCreate)
 				partCreate := storageItem{Child: len(storage) - 1, Path: part.Path, Type: type_PARTITION_NEW, FreeSpace: part.Size(),
 					Partition: part}
@@ -406,7 +680,7 @@ toScanLoop:
 	// Fix free space for extend filesystem. We can't detect it while scan - on the step the program doesn't know partition/LVM size
 	// Поправить свободной место файловой системы - оно не может быть определено просто во время, т.к. на этом шаге программа еще не знает размера нижележащего раздела/LVM
 	for _, item := range storage {
-		if item.Child != -1 && storage[item.Child].Type == type_FS {
+		if item.Child != -1 && (storage[item.Child].Type == type_FS || storage[item.Child].Type == type_SWAP) {
 			fs := &storage[item.Child]
 			switch {
 			case item.Size > fs.Size:
@@ -419,10 +693,35 @@ toScanLoop:
 		}
 	}
 
+	// Filesystem and swap can't be extended over their max size.
+	// Файловая система и swap не могут быть расширены больше своего максимального размера.
+	for i := range storage {
+		if storage[i].Type == type_FS || storage[i].Type == type_SWAP {
+			limitFreeSpace(&storage[i])
+		}
+	}
+
 	return storage, err
 }
 
+// Disk of partition or the device itself for whole disk. ok == false if disk is unknown.
+// Диск раздела или само устройство, если оно занимает весь диск. ok == false, если диск неизвестен.
+func deviceDisk(path string) (diskPath string, ok bool) {
+	if major, minor := getMajorMinor(path); major != 0 && getTypeByMajorMinor(major, minor) == type_DISK {
+		return path, true
+	}
+	diskPath, _, err := extractPartNumber(path)
+	return diskPath, err == nil
+}
+
 func extractPartNumber(path string) (diskPath string, partNumber uint32, err error) {
+	// Partition of device mapper disk: /dev/mapper/NAMEp1, /dev/dm-3
+	// Раздел диска device mapper: /dev/mapper/NAMEp1, /dev/dm-3
+	if major, minor := getMajorMinor(path); major != 0 {
+		if diskPath, partNumber, ok := kpartxPartitionDisk(major, minor); ok {
+			return diskPath, partNumber, nil
+		}
+	}
 	runePath := []rune(path)
 	if !unicode.IsDigit(runePath[len(runePath)-1]) {
 		return "", 0, fmt.Errorf("Can't extract part number from: %v", path)
@@ -443,178 +742,188 @@ func extractPartNumber(path string) (diskPath string, partNumber uint32, err err
 	return diskPath, uint32(partNumber64), nil
 }
 
-func fsGetSizeExt(path string) (size uint64, err error) {
-	var blockCount, blockSize uint64
-	var blockCountPresent, blockSizePresent bool
-	res := cmdTrimLines("tune2fs", "-l", path)
-	for _, line := range res {
-		if strings.HasPrefix(line, "Block size:") {
-			blockSizePresent = true
-			blockSizeString := strings.TrimSpace(line[len("Block size:"):])
-			blockSize, err = parseUint(blockSizeString)
-			if err != nil {
-				return
-			}
-		}
-		if strings.HasPrefix(line, "Block count:") {
-			blockCountString := strings.TrimSpace(line[len("Block count:"):])
-			blockCountPresent = true
-			blockCount, err = parseUint(blockCountString)
-			if err != nil {
-				return
-			}
-		}
+/*
+Read ext2/3/4 superblock. It read page cache of block device, so it is actual for mounted filesystem too.
+
+Читает суперблок ext2/3/4. Чтение идет через страничный кеш блочного устройства, поэтому актуально и для
+смонтированной файловой системы.
+*/
+func fsProbeExt(path string) (res probe.Result, err error) {
+	res, err = probe.ProbeFile(path)
+	if err != nil {
+		return res, fmt.Errorf("Can't read ext superblock: %v (%v)", path, err)
 	}
-	if !blockCountPresent || !blockSizePresent {
-		return 0, fmt.Errorf("Can't get filesistem size: %v", path)
+	switch res.Type {
+	case probe.TYPE_EXT2, probe.TYPE_EXT3, probe.TYPE_EXT4:
+		return res, nil
+	default:
+		return res, fmt.Errorf("Device doesn't contain ext filesystem: %v (%v)", path, res.Type)
 	}
-	size = blockCount * blockSize
-	return
+}
+
+func fsGetSizeExt(path string) (size uint64, err error) {
+	res, err := fsProbeExt(path)
+	return res.Size, err
+}
+
+// Return block size and features of ext2/3/4 filesystem.
+// Возвращает размер блока и список опций файловой системы ext2/3/4.
+func fsGetFeaturesExt(path string) (blockSize uint64, features []string, err error) {
+	res, err := fsProbeExt(path)
+	return res.BlockSize, res.Features, err
 }
 
 /*
 path - пусть к блочному устройству, на котором расположена xfs
 */
 func fsGetSizeXFS(path string) (size uint64, err error) {
-	var tmpMountPath string
-	if _, err = getMountPoint(path); err != nil {
-		tmpMountPath, err = ioutil.TempDir("", "")
-		if _, _, err = cmd("mount", "-t", "xfs", path, tmpMountPath); err != nil {
-			return 0, fmt.Errorf("(fsGetSizeXFS) Can't xfs mount: %v", err)
-		}
-	}
+	size, _, err = fsGetInfoXFS(path)
+	return size, err
+}
 
-	res := cmdTrimLines("xfs_info", path)
-	if tmpMountPath != "" {
-		cmd("umount", tmpMountPath)
-		os.Remove(tmpMountPath)
-	}
+/*
+Return size and block size of xfs. Mounted xfs keep superblock in own buffers, so its geometry read from kernel.
+Unmounted xfs read from superblock on disk.
 
-	for _, line := range res {
-		if !strings.HasPrefix(line, "data ") {
-			continue
-		}
-		// data     =                       bsize=4096   blocks=26240000, imaxpct=25
-		// field 0  field 1                 field 2      field 3          field 4
-		fields := strings.Fields(line)
-		if !strings.HasPrefix(fields[2], "bsize=") || !strings.HasPrefix(fields[3], "blocks=") {
-			continue
-		}
-		blockSize, err := parseUint(fields[2][len("bsize="):])
+Возвращает размер и размер блока xfs. Смонтированная xfs держит суперблок в своих буферах, поэтому ее геометрия
+читается из ядра. Отмонтированная xfs читается из суперблока на диске.
+*/
+func fsGetInfoXFS(path string) (size, blockSize uint64, err error) {
+	if mountPoint, err := getMountPoint(path); err == nil {
+		f, err := os.Open(mountPoint)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
-		blockCount, err := parseUint(fields[3][len("blocks=") : len(fields[3])-1]) // cut "blocks=" from start and "," from end.
+		defer f.Close()
+		geometry, err := fsGeometryXFS(f)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
+		return geometry.DataBlocks * uint64(geometry.BlockSize), uint64(geometry.BlockSize), nil
+	}
 
-		size = blockSize * blockCount
-		return size, nil
+	res, err := probe.ProbeFile(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Can't read xfs superblock: %v (%v)", path, err)
+	}
+	if res.Type != probe.TYPE_XFS {
+		return 0, 0, fmt.Errorf("Device doesn't contain xfs: %v (%v)", path, res.Type)
 	}
-	return 0, fmt.Errorf("I can't find size of xfs filesystem: ", path)
+	return res.Size, res.BlockSize, nil
 }
 
-// Return size of block device as it showed by kernel (in bytes)
-func getDiskSize(path string) uint64 {
-	for i := 0; i < TRY_COUNT; i++ {
-		if i > 0 {
-			log.Println("Try to read devsize once more: ", path)
-			time.Sleep(time.Second)
-		}
-		res, errString, err := cmd("blockdev", "--getsize64", path)
-		if err != nil {
-			log.Println("Error while call blockdev: ", path, err, errString)
-			continue
+// Return max size of filesystem by type, block size and features. 0 - unlimited.
+// Возвращает максимальный размер файловой системы. 0 - без ограничений.
+func fsMaxSize(fsType string, blockSize uint64, features []string) uint64 {
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		for _, feature := range features {
+			if feature == "64bit" {
+				return ext_MAX_SIZE_64BIT
+			}
 		}
-		size, err := parseUint(strings.TrimSpace(res))
-		if err == nil {
+		return ext_MAX_BLOCKS_32BIT * blockSize
+	case probe.TYPE_F2FS:
+		// 32-bit block addresses
+		// 32-битные адреса блоков
+		return 1 << 32 * blockSize
+	case probe.TYPE_VFAT:
+		// 28-bit cluster numbers of FAT32 and 32-bit count of 512-byte sectors
+		// 28-битные номера кластеров FAT32 и 32-битное количество 512-байтных секторов
+		if size := fat32_MAX_CLUSTERS * blockSize; size < fat_MAX_SECTORS*512 {
 			return size
-		} else {
-			log.Println("Can't read block device size:", path, err)
 		}
+		return fat_MAX_SECTORS * 512
+	case probe.TYPE_NTFS:
+		// 32-bit cluster numbers in Windows
+		// 32-битные номера кластеров в Windows
+		return (1<<32 - 1) * blockSize
+	default:
+		return 0
 	}
-	return 0
 }
 
 // return slice if all finded LVM PV
 // Возвращает список всех известных lvmPV
 func getLvmPV() []lvmPV {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("pvs", "-o", "pv_name,vg_name,pv_size", "--units", "B", "--separator", "|", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
-
 	res := make([]lvmPV, 0)
-	for _, line := range strings.Split(buf.String(), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		lineParts := strings.Split(line, "|")
-		sizeString := lineParts[2][:len(lineParts[2])-1]
-		size, err := parseUint(sizeString)
-		if err != nil {
-			log.Println("Can't parse size: ", line, err)
-			continue
-		}
-		res = append(res, lvmPV{Path: lineParts[0], VolumeGroup: lineParts[1], Size: size})
+	for _, pv := range lvmState.Get().PVs {
+		res = append(res, lvmPV{Path: pv.Name, VolumeGroup: pv.VGName, Size: uint64(pv.Size), Free: uint64(pv.Free)})
 	}
 	return res
 }
 
-func getMajorMinor(path string) (major, minor int) {
-	for {
-		linkDest, err := os.Readlink(path)
-		if err == nil {
-			if filepath.IsAbs(linkDest) {
-				path = linkDest
-			} else {
-				path = filepath.Join(filepath.Dir(path), linkDest)
-			}
-		} else {
-			break
-		}
-	}
-
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("stat", "-c", "%t:%T", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	bufParts := strings.Split(buf.String(), ":")
-	if len(bufParts) != 2 {
-		return 0, 0
-	}
-	majorInt64, _ := strconv.ParseInt(strings.TrimSpace(bufParts[0]), 16, 32)
-	minorInt64, _ := strconv.ParseInt(strings.TrimSpace(bufParts[1]), 16, 32)
-	return int(majorInt64), int(minorInt64)
-}
+/*
+Return mount point of filesystem on device. Mount of whole filesystem is preferred to bind mounts.
 
+Возвращает точку монтирования файловой системы устройства. Монтирование всей файловой системы предпочтительнее
+bind-монтирований.
+*/
 func getMountPoint(devPath string) (res string, err error) {
 	originalMajor, originalMinor := getMajorMinor(devPath)
 	if originalMajor == 0 {
-		return "", fmt.Errorf("Can't get original major/minor numbers: ", devPath)
+		return "", fmt.Errorf("Can't get original major/minor numbers: %v", devPath)
 	}
-
-	mountsBytes, err := ioutil.ReadFile("/proc/mounts")
+	mounts, err := readMountInfo()
 	if err != nil {
-		return
+		return "", err
 	}
 	// Find mount point of the partition
 	// Ищем точку монтирования указанного устройства
-	mounts := string(mountsBytes)
-	for _, line := range strings.Split(mounts, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	for _, mount := range mounts {
+		if mount.Major == 0 {
+			// Btrfs, tmpfs, proc, etc.
+			// Btrfs, tmpfs, proc и т.п.
+			if mount.Source != devPath {
+				continue
+			}
+		} else if mount.Major != originalMajor || mount.Minor != originalMinor {
 			continue
 		}
-		fields := strings.Fields(line)
-		major, minor := getMajorMinor(fields[0])
-		if major == originalMajor && minor == originalMinor {
-			return fields[0], nil
+		if res == "" || mount.Root == "/" {
+			res = mount.MountPoint
+		}
+		if mount.Root == "/" {
+			break
 		}
 	}
-	return "", fmt.Errorf("Can't find mountpoint of: ", devPath)
+	if res == "" {
+		return "", fmt.Errorf("Can't find mountpoint of: %v", devPath)
+	}
+	return res, nil
+}
+
+// Return mount points of mounted filesystems, which can be extended. Every filesystem returned once, bind mounts skipped.
+// Возвращает точки монтирования файловых систем, которые можно расширить. Каждая файловая система возвращается один
+// раз, bind-монтирования пропускаются.
+func getMountedFilesystems() (res []string) {
+	mounts, err := readMountInfo()
+	if err != nil {
+		log.Println("Can't read mounts:", err)
+		return nil
+	}
+	found := make(map[[2]int]int) // major,minor -> index in res
+	for _, mount := range mounts {
+		switch mount.FSType {
+		case "ext2", "ext3", "ext4", "xfs":
+		default:
+			continue
+		}
+		mm := [2]int{mount.Major, mount.Minor}
+		index, ok := found[mm]
+		switch {
+		case !ok:
+			found[mm] = len(res)
+			res = append(res, mount.MountPoint)
+		case mount.Root == "/" && mount.MountPoint != res[index]:
+			// First found mount was bind mount
+			// Первым найдено bind-монтирование
+			if _, _, err := resolvePath(res[index]); err == nil {
+				res[index] = mount.MountPoint
+			}
+		}
+	}
+	return res
 }
 
 // Find and return partitions for create.
@@ -632,7 +941,12 @@ func getNewPartitions() (res []partition) {
 			return nil
 		}
 		major, minor := getMajorMinor(path)
-		if getTypeByMajorMinor(major, minor) != type_DISK {
+		if diskType := getTypeByMajorMinor(major, minor); diskType != type_DISK && diskType != type_LOOP {
+			return nil
+		}
+		// Filesystem, PV, etc. on whole disk - it hasn't partition table
+		// Файловая система, PV и т.п. на всем диске - у него нет таблицы разделов
+		if probeType(path) != "" {
 			return nil
 		}
 
@@ -696,6 +1010,9 @@ func getTypeByMajorMinor(major, minor int) storageItemType {
 
 	switch major {
 	case 7:
+		if _, ok := readLoopInfo(major, minor); ok {
+			return type_LOOP
+		}
 		return type_DISK
 	case 3, 22, 33, 34, 56, 57, 88, 89, 90, 91:
 		if minor%64 == 0 {
@@ -709,42 +1026,202 @@ func getTypeByMajorMinor(major, minor int) storageItemType {
 		} else {
 			return type_PARTITION
 		}
-	case 259:
+	}
+	if dm, ok := readDMInfo(major, minor); ok {
+		if _, ok := kpartxPartNumber(dm.UUID); ok {
+			return type_PARTITION
+		}
+		if dmGeneric(dm) {
+			return type_DM
+		}
+	}
+	// nvme, virtio, xen and other disks: dynamic or shared numbers, detect by sysfs
+	// nvme, virtio, xen и другие диски: динамические или общие номера, определяем по sysfs
+	return sysBlockType(fmt.Sprintf("/sys/dev/block/%v:%v", major, minor))
+}
+
+// Partition has file partition in sysfs, disk has link to hardware device. Device mapper, md - unknown.
+// У раздела есть файл partition в sysfs, у диска - ссылка на устройство. Device mapper, md - неизвестный тип.
+func sysBlockType(sysDir string) storageItemType {
+	if _, err := os.Stat(filepath.Join(sysDir, "partition")); err == nil {
 		return type_PARTITION
 	}
+	if _, err := os.Stat(filepath.Join(sysDir, "device")); err == nil {
+		return type_DISK
+	}
 	return type_UNKNOWN
 }
 
 // Path - VolumeGroup/VolumeName
 func lvmLVGetSize(path string) uint64 {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("lvs", "-o", "vg_name,lv_name,lv_size", "--units", "B", "--separator", "/", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
-	needPrefix := path + "/"
-	for _, line := range strings.Split(buf.String(), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, needPrefix) {
-			sizeString := line[len(needPrefix) : len(line)-1]
-			lvmSize, err := strconv.ParseUint(sizeString, 10, 64)
-			if err != nil {
-				log.Println("Can't parse lvm size: ", path, sizeString, err)
-				return 0
-			}
-			return lvmSize
-		}
+	if lv, ok := lvmState.Get().LV(path); ok {
+		return uint64(lv.Size)
 	}
 	log.Println("Can't find lvm: " + path)
 	return 0
 }
 
-func lvmPVCalcSize(partitionSize, extentSize uint64) (pvSize uint64) {
-	extentCount := partitionSize / extentSize
-	if lvm_PV_METADATA_RESERVED > extentCount {
+// Path - VolumeGroup/VolumeName. Return geometry of last segment of LV.
+// Возвращает геометрию последнего сегмента LV.
+func lvmLVGetInfo(path string) (lv lvmLV) {
+	lv.Path = path
+	for _, seg := range lvmState.Get().Segs {
+		if seg.Path() != path {
+			continue
+		}
+		// Take last segment
+		// Берем последний сегмент
+		lv.SegType = seg.SegType
+		lv.Stripes = uint64(seg.Stripes)
+		lv.StripeSize = uint64(seg.StripeSize)
+		pool := strings.Trim(seg.PoolLV, "[]")
+		switch {
+		case pool == "":
+			// pass
+		case lv.SegType == "thin":
+			lv.Pool = seg.VGName + "/" + pool
+		case lvmIsCache(lv.SegType):
+			lv.CachePool = seg.VGName + "/" + pool
+			lv.CacheMode = seg.CacheMode
+		}
+		lv.MetaSize = uint64(seg.MetaSize)
+	}
+	if lv.SegType == "" {
+		log.Println("Can't find lvm segments: " + path)
+	}
+	if lvmIsRaid(lv.SegType) {
+		lv.ImagePVs = lvmLVGetImagePVs(path)
+	}
+	return lv
+}
+
+/*
+Return devices of all LVs of volume group (including hidden). Key - VolumeGroup/VolumeName, value - PV paths and
+names of sub-LVs (without brackets). lvs -a show hidden sub-LVs with names in brackets: [lv_rimage_0].
+
+Возвращает устройства всех LV группы томов (включая скрытые). Ключ - VolumeGroup/VolumeName, значение - пути к PV и
+имена вложенных LV (без скобок). lvs -a показывает скрытые LV с именами в квадратных скобках: [lv_rimage_0].
+*/
+func lvmLVDevices(vgName string) map[string][]string {
+	devices := make(map[string][]string)
+	for _, seg := range lvmState.Get().Segs {
+		if seg.VGName != vgName {
+			continue
+		}
+		name := seg.Path()
+		for _, dev := range strings.Split(seg.Devices, ",") {
+			// Device format: /dev/sda1(0) or [lv_rimage_0](0)
+			// Формат устройства: /dev/sda1(0) или [lv_rimage_0](0)
+			if bracket := strings.Index(dev, "("); bracket != -1 {
+				dev = dev[:bracket]
+			}
+			dev = strings.Trim(strings.TrimSpace(dev), "[]")
+			if dev != "" {
+				devices[name] = append(devices[name], dev)
+			}
+		}
+	}
+	return devices
+}
+
+// Return PVs of LV and all its sub-LVs. devices - result of lvmLVDevices.
+// Возвращает PV, на которых расположен LV и все вложенные в него LV. devices - результат lvmLVDevices.
+func lvmLVPVs(devices map[string][]string, path string) (res []string) {
+	vgName := path[:strings.Index(path, "/")]
+	for deep, toCheck := 0, []string{path}; len(toCheck) > 0 && deep < max_STORAGE_DEEP; deep++ {
+		name := toCheck[len(toCheck)-1]
+		toCheck = toCheck[:len(toCheck)-1]
+		for _, dev := range devices[name] {
+			if filepath.IsAbs(dev) {
+				res = append(res, dev)
+			} else {
+				toCheck = append(toCheck, vgName+"/"+dev)
+			}
+		}
+	}
+	return res
+}
+
+/*
+Path - VolumeGroup/VolumeName of raid or mirror LV. Return PVs of every image (leg) of the LV.
+
+Возвращает PV каждого образа raid или mirror LV.
+*/
+func lvmLVGetImagePVs(path string) (res [][]string) {
+	vgName := path[:strings.Index(path, "/")]
+	devices := lvmLVDevices(vgName)
+	for _, image := range devices[path] {
+		if !strings.Contains(image, "_rimage_") && !strings.Contains(image, "_mimage_") {
+			continue
+		}
+		res = append(res, lvmLVPVs(devices, vgName+"/"+image))
+	}
+	return res
+}
+
+/*
+Path - VolumeGroup/PoolName of cache pool or cache volume. Return PVs of the pool and its sub-LVs (_cdata, _cmeta).
+
+Возвращает PV пула или тома кеша и его вложенных LV (_cdata, _cmeta).
+*/
+func lvmCacheGetPVs(path string) (res []string) {
+	vgName := path[:strings.Index(path, "/")]
+	devices := lvmLVDevices(vgName)
+	for name := range devices {
+		if name == path || strings.HasPrefix(name, path+"_") {
+			res = append(res, lvmLVPVs(devices, name)...)
+		}
+	}
+	return res
+}
+
+/*
+Usable size of PV on device with size devSize: whole extents between pe_start and second metadata area.
+
+Полезный размер PV на устройстве размером devSize: целые экстенты между pe_start и второй областью метаданных.
+*/
+func lvmPVCalcSize(devSize uint64, geometry lvmPVGeometry, extentSize uint64) (pvSize uint64) {
+	if extentSize == 0 || devSize <= geometry.PEStart+geometry.MDAEnd {
 		return 0
 	}
-	extentCount -= lvm_PV_METADATA_RESERVED
-	return extentCount * extentSize
+	return (devSize - geometry.PEStart - geometry.MDAEnd) / extentSize * extentSize
+}
+
+/*
+Read geometry of existing PV from PV header on device. If it can't be read - from LVM report.
+
+Читает геометрию существующего PV из заголовка PV на устройстве. Если его не удалось прочитать - из отчета LVM.
+*/
+func lvmPVReadGeometry(path string) (geometry lvmPVGeometry) {
+	if res, err := probe.ProbeFile(path); err == nil && res.Type == probe.TYPE_LVM2 && len(res.LVMDataAreas) > 0 {
+		geometry.PEStart = res.LVMDataAreas[0].Offset
+		for _, mda := range res.LVMMetadataAreas {
+			// Metadata area after data - second copy at end of device
+			// Область метаданных после данных - вторая копия в конце устройства
+			if mda.Offset > geometry.PEStart && res.Size > mda.Offset {
+				geometry.MDAEnd = res.Size - mda.Offset
+			}
+		}
+		return geometry
+	}
+
+	pv, ok := lvmState.Get().PV(path)
+	if !ok || pv.PEStart == 0 {
+		log.Println("Can't read PV geometry, use geometry of new PV:", path)
+		return lvmNewPVGeometry
+	}
+	geometry.PEStart = uint64(pv.PEStart)
+	if pv.MDACount > 1 {
+		geometry.MDAEnd = uint64(pv.MDASize)
+	}
+	return geometry
+}
+
+// Options of pvcreate for new PV. Geometry of the PV is lvmNewPVGeometry.
+// Параметры pvcreate для нового PV. Геометрия такого PV - lvmNewPVGeometry.
+func lvmPVCreateArgs(path string) []string {
+	return []string{"--dataalignment", formatUInt(lvm_NEW_PV_DATA_ALIGNMENT/1024) + "k",
+		"--metadatasize", formatUInt(lvm_NEW_PV_METADATA_SIZE/1024) + "k", "--metadatacopies", "1", path}
 }
 
 // From time to time pvs no return size of the pvs and return it in next call or after few seconds.
@@ -753,42 +1230,63 @@ func lvmPVGetSize(path string) uint64 {
 	if res == 0 {
 		log.Println("Error while get pvsize, try again: ", path)
 		time.Sleep(5 * time.Second)
+		lvmState.Invalidate()
 		res = lvmPVGetSizeTry(path)
 	}
 	return res
 }
 
 func lvmPVGetSizeTry(path string) uint64 {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("pvs", "-o", "pv_size", "--units", "B", "--separator", "|", "--noheading", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	line := strings.TrimSuffix(strings.TrimSpace(buf.String()), "B")
-	pvSize, err := strconv.ParseUint(line, 10, 64)
-	if err != nil {
-		log.Println("Can't parse pv size: ", path, line, err)
+	pv, ok := lvmState.Get().PV(path)
+	if !ok {
+		log.Println("Can't find pv: ", path)
 		return 0
 	}
-	return pvSize
-	log.Println("Can't find pv size: ", path, ":\n", buf.String())
-	return 0
+	return uint64(pv.Size)
 }
 
 func lvmVGGetSize(vgName string) (size, freeSize, extentSize uint64) {
-	res := cmdTrimLines("vgs", "--units", "B", "--separator", "/", "--noheading", "-o", "vg_name,vg_size,vg_free,vg_extent_size")
-	for _, line := range res {
-		lineParts := strings.Split(line, "/")
-		if lineParts[0] == vgName {
-			size, _ = parseUint(strings.TrimSuffix(lineParts[1], "B"))
-			freeSize, _ = parseUint(strings.TrimSuffix(lineParts[2], "B"))
-			extentSize, _ = parseUint(strings.TrimSuffix(lineParts[3], "B"))
-			return
-		}
+	if vg, ok := lvmState.Get().VG(vgName); ok {
+		return uint64(vg.Size), uint64(vg.Free), uint64(vg.ExtentSize)
 	}
 	log.Printf("Can't get VG size, can't find volume group: '%v'\n", vgName)
 	return 0, 0, 0
 }
 
+/*
+Cut FreeSpace of item by MaxSize. Cutted space store in OverLimit. It can be called many times, for example after change
+FreeSpace or MaxSize.
+
+Обрезает FreeSpace по MaxSize. Отрезанное место сохраняется в OverLimit. Может вызываться многократно, например после
+изменения FreeSpace или MaxSize.
+*/
+func limitFreeSpace(item *storageItem) {
+	total := item.FreeSpace + item.OverLimit
+	switch {
+	case item.MaxSize == 0 || item.Size+total <= item.MaxSize:
+		item.FreeSpace = total
+	case item.Size >= item.MaxSize:
+		item.FreeSpace = 0
+	default:
+		item.FreeSpace = item.MaxSize - item.Size
+	}
+	item.OverLimit = total - item.FreeSpace
+}
+
+/*
+Take growth from FreeSpace. Tool can grow device more then planned (alignment, own rounding), then FreeSpace becomes 0.
+
+Вычитает рост из FreeSpace. Утилита может увеличить устройство больше запланированного (выравнивание, собственное
+округление), тогда FreeSpace становится 0.
+*/
+func useFreeSpace(item *storageItem, growth uint64) {
+	if growth > item.FreeSpace {
+		item.FreeSpace = 0
+		return
+	}
+	item.FreeSpace -= growth
+}
+
 func parseUint(s string) (res uint64, err error) {
 	return strconv.ParseUint(s, 10, 64)
 }
@@ -797,14 +1295,13 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 	disk.Path = path
 	disk.Major, disk.Minor = getMajorMinor(path)
 
-	blockSizeString, _, _ := cmd("blockdev", "--getss", disk.Path)
-	disk.SectorSizeLogical, err = strconv.ParseUint(strings.TrimSpace(blockSizeString), 10, 64)
+	geometry, err := getBlockDevGeometry(disk.Path)
 	if err != nil {
-		log.Println("Can't get block size:", disk.Path, err)
+		log.Println("Can't get disk geometry:", disk.Path, err)
 		return
 	}
-
-	disk.Size = getDiskSize(disk.Path)
+	disk.Size, disk.SectorSizeLogical, disk.SectorSizePhysical = geometry.Size, geometry.SectorSizeLogical,
+		geometry.SectorSizePhysical
 	if disk.Size == 0 {
 		log.Println("Can't get disk size:", disk.Path)
 		return
@@ -835,6 +1332,7 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 			log.Println("Can't read gpt table: ", disk.Path)
 			return
 		}
+		disk.DiskID = gptTable.Header.DiskGUID.String()
 		firstUsableDiskByte = gptTable.Header.FirstUsableLBA * disk.SectorSizeLogical
 		lastUsableDiskByte = disk.Size - disk.SectorSizeLogical /*GPT Header sector*/ - uint64(gptTable.Header.PartitionEntrySize)*uint64(gptTable.Header.PartitionsArrLen) - 1
 		if (lastUsableDiskByte+1)%disk.SectorSizeLogical != 0 {
@@ -849,12 +1347,22 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 				Number:    uint32(i + 1),
 				FirstByte: gptPart.FirstLBA * disk.SectorSizeLogical,
 				LastByte:  gptPart.LastLBA*disk.SectorSizeLogical + disk.SectorSizeLogical - 1,
+				UUID:      gptPart.Id.String(),
+				Label:     strings.TrimRight(gptPart.Name(), "\x00"),
 			}
 			part.Path = part.makePath()
 			disk.Partitions = append(disk.Partitions, part)
 		}
 	case err == nil && !mbrTable.IsGPT(): // If it is msdos table
 		disk.PartTable = "msdos"
+		// Disk signature at offset 440, show it as blkid PTUUID
+		// Сигнатура диска по смещению 440, показываем ее как PTUUID в blkid
+		signature := make([]byte, 4)
+		if _, err = diskFile.ReadAt(signature, 440); err != nil {
+			log.Println("Can't read msdos disk signature:", disk.Path, err)
+			return
+		}
+		disk.DiskID = fmt.Sprintf("%02x%02x%02x%02x", signature[3], signature[2], signature[1], signature[0])
 		firstUsableDiskByte = 512 * 63 // As parted - align for can convert to GPT in feauture.
 		lastUsableDiskByte = disk.Size - 1
 		for i, mbrPart := range mbrTable.GetAllPartitions() {
@@ -866,6 +1374,7 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 				Number:    uint32(i + 1),
 				FirstByte: uint64(mbrPart.GetLBAStart()) * disk.SectorSizeLogical,
 				LastByte:  (uint64(mbrPart.GetLBAStart())+uint64(mbrPart.GetLBALen()))*disk.SectorSizeLogical - 1,
+				UUID:      fmt.Sprintf("%v-%02x", disk.DiskID, i+1),
 			}
 			part.Path = part.makePath()
 			disk.Partitions = append(disk.Partitions, part)
@@ -925,55 +1434,37 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 // Сканирует LVM_LV, запоминает их major,minor номера устройств. Для надёжного определения что блочное устройство это
 // LVM/не LVM.
 func scanLVM() {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("lvs", "-a", "-o", "vg_name,lv_name,lv_kernel_major,lv_kernel_minor,lv_size", "--units", "B", "--separator", "/", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
-	for _, line := range strings.Split(buf.String(), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		lineParts := strings.Split(line, "/")
-		path := lineParts[0] + "/" + lineParts[1]
-		major, err := strconv.Atoi(lineParts[2])
-		if err != nil {
-			log.Println("scanLVM, Can't parse lvs (major): ", line, err)
-			continue
-		}
-		minor, err := strconv.Atoi(lineParts[3])
-		if err != nil {
-			log.Println("scanLVM, Can't parse lvs (minor): ", line, err)
-			continue
-		}
-		sizeString := lineParts[4][:len(lineParts[4])-1]
-		size, err := strconv.ParseUint(sizeString, 10, 64)
-		if err != nil {
-			log.Println("scanLVM, Can't parse lvs (size): ", line, err)
+	for _, lv := range lvmState.Reload().LVs {
+		major, minor, ok := lv.MajorMinor()
+		if !ok {
 			continue
 		}
-		majorMinorDeviceTypeCache[[2]int{major, minor}] = storageItem{Path: path, Type: type_LVM_LV, Size: size}
+		majorMinorDeviceTypeCache[[2]int{major, minor}] = storageItem{Path: lv.Path(), Type: type_LVM_LV, Size: uint64(lv.Size)}
 	}
 }
 
+// Follow all levels of symlinks: /dev/disk/by-id/dm-name-vg-lv -> ../../dm-0.
+// Проходит все уровни символических ссылок: /dev/disk/by-id/dm-name-vg-lv -> ../../dm-0.
 func readLink(path string) (res string, err error) {
-	stat, err := os.Lstat(path)
-	if err != nil {
-		log.Println("Can't stat while Readlink: ", path, err)
-		return path, err
-	}
-	if stat.Mode()&os.ModeSymlink == os.ModeSymlink {
-		res, err = os.Readlink(path)
+	res = path
+	for deep := 0; deep < max_SYMLINK_DEEP; deep++ {
+		stat, err := os.Lstat(res)
 		if err != nil {
-			log.Println("Can't readlink:", path, err)
-			return
+			log.Println("Can't stat while Readlink: ", res, err)
+			return res, err
 		}
-		if !filepath.IsAbs(res) {
-			res = filepath.Join(filepath.Dir(path), res)
+		if stat.Mode()&os.ModeSymlink != os.ModeSymlink {
+			return res, nil
 		}
-	} else {
-		return path, nil
+		target, err := os.Readlink(res)
+		if err != nil {
+			log.Println("Can't readlink:", res, err)
+			return res, err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(res), target)
+		}
+		res = target
 	}
-
-	return
+	return res, fmt.Errorf("Too many levels of symbolic links: %v", path)
 }
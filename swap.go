@@ -0,0 +1,215 @@
+package fsextender
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/rekby/fsextender/probe"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Max count of pages in swap header: last_page is 32 bit.
+// Максимальное количество страниц в заголовке swap: last_page 32-битный.
+const swap_MAX_PAGES = 1 << 32
+
+// RAM, which have to stay free after swapoff.
+// Память, которая должна остаться свободной после swapoff.
+const swap_RAM_RESERVE = 128 * 1024 * 1024
+
+// Line of /proc/swaps.
+// Строка /proc/swaps.
+type swapInfo struct {
+	Path     string
+	Type     string // partition/file
+	Size     uint64
+	Used     uint64
+	Priority int
+}
+
+func readSwaps() ([]swapInfo, error) {
+	f, err := os.Open("/proc/swaps")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseSwaps(f)
+}
+
+/*
+Parse /proc/swaps. Sizes in KiB, path escaped as in mountinfo:
+Filename        Type        Size     Used    Priority
+/dev/dm-1       partition   8388604  1024    -2
+
+Разбирает /proc/swaps. Размеры в KiB, путь экранирован как в mountinfo.
+*/
+func parseSwaps(r io.Reader) (res []swapInfo, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "Filename" {
+			continue
+		}
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("Bad line of swaps: %v", scanner.Text())
+		}
+		swap := swapInfo{Path: unescapeMountField(fields[0]), Type: fields[1]}
+		swap.Size, err = parseUint(fields[2])
+		if err == nil {
+			swap.Used, err = parseUint(fields[3])
+		}
+		if err == nil {
+			swap.Priority, err = strconv.Atoi(fields[4])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Bad line of swaps: %v", scanner.Text())
+		}
+		swap.Size *= 1024
+		swap.Used *= 1024
+		res = append(res, swap)
+	}
+	return res, scanner.Err()
+}
+
+// Active swap on the device. Compare by device numbers, because path in /proc/swaps can be other link (/dev/dm-1).
+// Активный swap на устройстве. Сравнение по номерам устройства, т.к. путь в /proc/swaps может быть другой ссылкой.
+func swapActive(path string) (swap swapInfo, ok bool) {
+	swaps, err := readSwaps()
+	if err != nil {
+		log.Println("Can't read active swaps:", err)
+		return swap, false
+	}
+	major, minor := getMajorMinor(path)
+	for _, swap = range swaps {
+		if swap.Type != "partition" {
+			continue
+		}
+		if swapMajor, swapMinor := getMajorMinor(swap.Path); swapMajor == major && swapMinor == minor {
+			return swap, true
+		}
+	}
+	return swap, false
+}
+
+// Available RAM from /proc/meminfo (MemAvailable).
+// Доступная память из /proc/meminfo (MemAvailable).
+func memAvailable() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return parseMemAvailable(f)
+}
+
+func parseMemAvailable(r io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			res, err := parseUint(fields[1])
+			return res * 1024, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemAvailable not found in meminfo")
+}
+
+// Pages of swap have to fit in available RAM with reserve.
+// Страницы swap должны поместиться в доступную память с запасом.
+func swapCanOff(swap swapInfo, available uint64) error {
+	if available < swap.Used+swap_RAM_RESERVE {
+		return fmt.Errorf("Not enough free RAM for swapoff %v: used %v, available %v, need reserve %v", swap.Path,
+			formatSize(swap.Used), formatSize(available), formatSize(swap_RAM_RESERVE))
+	}
+	return nil
+}
+
+/*
+Turn off active swaps, which will be extended by plan. Swap can't change size while it used, so it turned off before
+underlying layers extended, and turned on by step of swap. If RAM isn't enough for swapoff - all plan is skipped.
+
+Отключает активные swap, которые будут расширены по плану. Swap не может изменить размер во время работы, поэтому он
+отключается до расширения нижележащих слоев и включается шагом swap. Если памяти для swapoff не хватает - весь план
+отменяется.
+*/
+func swapPrepare(plan []storageItem) {
+	for i, item := range planPropagateFreeSpace(plan) {
+		if item.Type != type_SWAP {
+			continue
+		}
+		// Step of swap turn on only swaps, which turned off here
+		// Шаг swap включает только те swap, которые отключены здесь
+		plan[i].SwapActive = false
+		if item.FreeSpace == 0 {
+			continue
+		}
+		swap, ok := swapActive(item.Path)
+		if !ok {
+			continue
+		}
+		available, err := memAvailable()
+		if err == nil {
+			err = swapCanOff(swap, available)
+		}
+		if err == nil {
+			var res, stderr string
+			res, stderr, err = cmd("swapoff", item.Path)
+			if err != nil {
+				err = fmt.Errorf("Can't swapoff %v: %v\nstdout: %v\nstderr: %v", item.Path, err, res, stderr)
+			}
+		}
+		if err != nil {
+			log.Println(err)
+			for j := range plan {
+				if plan[j].Type != type_SKIP && plan[j].Type != type_UNKNOWN {
+					skipStorageItem(&plan[j], "Swap can't be turned off: "+item.Path)
+				}
+			}
+			return
+		}
+		log.Printf("Swap turned off for extend: %v\n", item.Path)
+		plan[i].SwapActive = true
+		plan[i].SwapPriority = swap.Priority
+	}
+}
+
+// Make swap on whole device with same UUID and label.
+// Создает swap на всем устройстве с тем же UUID и меткой.
+func swapFormat(path string) error {
+	old, err := probe.ProbeFile(path)
+	if err != nil || old.Type != probe.TYPE_SWAP {
+		return fmt.Errorf("Device doesn't contain swap: %v", path)
+	}
+	var args []string
+	if old.UUID != "" {
+		args = append(args, "-U", old.UUID)
+	}
+	if old.Label != "" {
+		args = append(args, "-L", old.Label)
+	}
+	args = append(args, path)
+	res, stderr, err := cmd("mkswap", args...)
+	if err != nil {
+		return fmt.Errorf("mkswap %v: %v\nstdout: %v\nstderr: %v", strings.Join(args, " "), err, res, stderr)
+	}
+	return nil
+}
+
+// Turn on swap with previous priority. Negative priority is set by kernel.
+// Включает swap с прежним приоритетом. Отрицательный приоритет назначает ядро.
+func swapOn(path string, priority int) error {
+	args := []string{path}
+	if priority >= 0 {
+		args = []string{"-p", strconv.Itoa(priority), path}
+	}
+	res, stderr, err := cmd("swapon", args...)
+	if err != nil {
+		return fmt.Errorf("swapon %v: %v\nstdout: %v\nstderr: %v", strings.Join(args, " "), err, res, stderr)
+	}
+	return nil
+}